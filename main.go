@@ -8,21 +8,40 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/build"
+	"go/token"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
 
-	"golang.org/x/lint"
+	lint "golang.org/x/lint/src"
 )
 
 var (
 	minConfidence = flag.Float64("min_confidence", 0.8, "minimum confidence of a problem to print it")
 	setExitStatus = flag.Bool("set_exit_status", false, "set exit status to 1 if any issues are found")
+	ignoredErrors = flag.String("ignored_errors", "", "comma-separated list of functions whose error results may be dropped, such as (*os.File).Close")
+	errorCtors    = flag.String("error_constructors", "", "comma-separated list of functions besides errors.New and fmt.Errorf that make an error from a message")
+	maxParams     = flag.Int("max_params", 0, "number of parameters above which a function is reported (0 means the default)")
+	maxResults    = flag.Int("max_results", 0, "number of results above which a function is reported (0 means the default)")
+	maxSameTyped  = flag.Int("max_same_typed_params", 0, "number of adjacent bool or integer parameters of one type above which a function is reported (0 means the default)")
+	maxCyclomatic = flag.Int("max_cyclomatic", 0, "cyclomatic complexity above which a function is reported (0 means the default)")
+	maxCognitive  = flag.Int("max_cognitive", 0, "cognitive complexity above which a function is reported (0 means the default)")
+	maxNakedLines = flag.Int("max_naked_return_lines", 0, "length in lines of a function above which its naked returns are reported (0 means the default)")
+	allowedNums   = flag.String("allowed_numbers", "", "comma-separated list of numbers besides 0, 1, -1 and 2 that need no named constant, such as 10,-5,0.5")
+	typeErrors    = flag.Bool("type_errors", false, "report type-checking errors, and the rules whose results may be incomplete because of them")
+	statsFormat   = flag.String("stats", "", `print the complexity of every function instead of suggestions, as a "table" or as "json"`)
+	disabledRules = flag.String("disable", "", "comma-separated list of rules not to run, such as nil-deref")
+	jsonOutput    = flag.Bool("json", false, "print the suggestions as a JSON array, with their related positions and suggested edits")
 	suggestions   int
+	problems      []lint.Problem
+	funcStats     []lint.FuncStats
 )
 
 func usage() {
@@ -38,6 +57,10 @@ func usage() {
 func main() {
 	flag.Usage = usage
 	flag.Parse()
+	if *statsFormat != "" && *statsFormat != "table" && *statsFormat != "json" {
+		usage()
+		os.Exit(2)
+	}
 
 	if flag.NArg() == 0 {
 		lintDir(".")
@@ -83,12 +106,70 @@ func main() {
 		}
 	}
 
+	if *statsFormat != "" {
+		if err := printStats(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *jsonOutput {
+		printProblemsJSON()
+	}
 	if *setExitStatus && suggestions > 0 {
 		fmt.Fprintf(os.Stderr, "Found %d lint suggestions; failing.\n", suggestions)
 		os.Exit(1)
 	}
 }
 
+// newLinter returns a Linter configured from the command-line flags.
+func newLinter() *lint.Linter {
+	l := new(lint.Linter)
+	if *ignoredErrors != "" {
+		l.IgnoredErrors = strings.Split(*ignoredErrors, ",")
+	}
+	if *errorCtors != "" {
+		l.ErrorConstructors = strings.Split(*errorCtors, ",")
+	}
+	l.MaxParams = *maxParams
+	l.MaxResults = *maxResults
+	l.MaxSameTypedParams = *maxSameTyped
+	l.MaxCyclomatic = *maxCyclomatic
+	l.MaxCognitive = *maxCognitive
+	l.MaxNakedReturnLines = *maxNakedLines
+	if *allowedNums != "" {
+		l.AllowedNumbers = strings.Split(*allowedNums, ",")
+	}
+	l.ReportTypeErrors = *typeErrors
+	if *statsFormat != "" {
+		l.FuncStats = func(s lint.FuncStats) { funcStats = append(funcStats, s) }
+	}
+	if *disabledRules != "" {
+		l.Disabled = make(map[string]bool)
+		for _, name := range strings.Split(*disabledRules, ",") {
+			l.Disabled[name] = true
+		}
+	}
+	return l
+}
+
+// printProblem prints p in the quickfix format,
+// followed by a line for each of its related positions.
+// With -json, p is kept to be printed by printProblemsJSON instead.
+func printProblem(p lint.Problem) {
+	if *statsFormat != "" {
+		return
+	}
+	if *jsonOutput {
+		problems = append(problems, p)
+		return
+	}
+	fmt.Printf("%v: %s\n", p.Position, p.Text)
+	for _, r := range p.Related {
+		fmt.Printf("%v: note: %s\n", r.Position, r.Text)
+	}
+}
+
 func InvalidSlices(slice1 []string, slice2 []int) (bool, int) {
 	if slice1 == nil {
 		return false, 0
@@ -104,6 +185,50 @@ func InvalidSlices(slice1 []string, slice2 []int) (bool, int) {
 	return true, 1
 }
 
+func isDir(filename string) bool {
+	fi, err := os.Stat(filename)
+	return err == nil && fi.IsDir()
+}
+
+func exists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+func lintFiles(filenames ...string) {
+	files := make(map[string][]byte)
+	for _, filename := range filenames {
+		src, err := ioutil.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		files[filename] = src
+	}
+
+	ps, err := newLinter().LintFiles(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	for _, p := range ps {
+		if p.Confidence >= *minConfidence {
+			printProblem(p)
+			suggestions++
+		}
+	}
+}
+
+func lintDir(dirname string) {
+	pkg, err := build.ImportDir(dirname, 0)
+	lintImportedPackage(pkg, err)
+}
+
+func lintPackage(pkgname string) {
+	pkg, err := build.Import(pkgname, ".", 0)
+	lintImportedPackage(pkg, err)
+}
+
 func lintImportedPackage(pkg *build.Package, err error) {
 	if pkg == nil {
 		return
@@ -130,3 +255,86 @@ func lintImportedPackage(pkg *build.Package, err error) {
 
 	lintFiles(files...)
 }
+
+// printProblemsJSON prints the problems kept by printProblem as a JSON array.
+// Positions are given by file, line and column, and edits also by the byte
+// offsets of the text they replace.
+func printProblemsJSON() {
+	type positionJSON struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	}
+	type relatedJSON struct {
+		positionJSON
+		Text string `json:"text"`
+	}
+	type editJSON struct {
+		positionJSON
+		Offset    int    `json:"offset"`
+		EndOffset int    `json:"end_offset"`
+		New       string `json:"new"`
+	}
+	type problemJSON struct {
+		positionJSON
+		Text            string        `json:"text"`
+		Link            string        `json:"link,omitempty"`
+		Confidence      float64       `json:"confidence"`
+		Category        string        `json:"category"`
+		ReplacementLine string        `json:"replacement_line,omitempty"`
+		Related         []relatedJSON `json:"related,omitempty"`
+		Edits           []editJSON    `json:"edits,omitempty"`
+	}
+	pos := func(p token.Position) positionJSON { return positionJSON{p.Filename, p.Line, p.Column} }
+	out := make([]problemJSON, 0, len(problems))
+	for _, p := range problems {
+		pj := problemJSON{
+			positionJSON:    pos(p.Position),
+			Text:            p.Text,
+			Link:            p.Link,
+			Confidence:      p.Confidence,
+			Category:        p.Category,
+			ReplacementLine: p.ReplacementLine,
+		}
+		for _, r := range p.Related {
+			pj.Related = append(pj.Related, relatedJSON{pos(r.Position), r.Text})
+		}
+		for _, e := range p.Edits {
+			pj.Edits = append(pj.Edits, editJSON{pos(e.Pos), e.Pos.Offset, e.End.Offset, e.New})
+		}
+		out = append(out, pj)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// printStats writes the complexity metrics collected while linting to w,
+// in the format selected by the -stats flag.
+func printStats(w io.Writer) error {
+	if *statsFormat == "json" {
+		type funcJSON struct {
+			File       string `json:"file"`
+			Line       int    `json:"line"`
+			Func       string `json:"func"`
+			Cyclomatic int    `json:"cyclomatic"`
+			Cognitive  int    `json:"cognitive"`
+		}
+		out := make([]funcJSON, 0, len(funcStats))
+		for _, s := range funcStats {
+			out = append(out, funcJSON{s.Position.Filename, s.Position.Line, s.Name, s.Cyclomatic, s.Cognitive})
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(out)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CYCLOMATIC\tCOGNITIVE\tFUNC\tPOSITION")
+	for _, s := range funcStats {
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%v\n", s.Cyclomatic, s.Cognitive, s.Name, s.Position)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2013 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestStats checks the output of -stats in both formats, for functions
+// that exceed the limits on each metric, a method, and one within them.
+func TestStats(t *testing.T) {
+	const filename = "src/testdata/complexity.go"
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", `CYCLOMATIC  COGNITIVE  FUNC          POSITION
+17          1          verbName      src/testdata/complexity.go:10:6
+7           21         nested        src/testdata/complexity.go:49:6
+9           21         scanner.scan  src/testdata/complexity.go:69:19
+6           15         limits        src/testdata/complexity.go:92:6
+`},
+		{"json", `[
+	{
+		"file": "src/testdata/complexity.go",
+		"line": 10,
+		"func": "verbName",
+		"cyclomatic": 17,
+		"cognitive": 1
+	},
+	{
+		"file": "src/testdata/complexity.go",
+		"line": 49,
+		"func": "nested",
+		"cyclomatic": 7,
+		"cognitive": 21
+	},
+	{
+		"file": "src/testdata/complexity.go",
+		"line": 69,
+		"func": "scanner.scan",
+		"cyclomatic": 9,
+		"cognitive": 21
+	},
+	{
+		"file": "src/testdata/complexity.go",
+		"line": 92,
+		"func": "limits",
+		"cyclomatic": 6,
+		"cognitive": 15
+	}
+]
+`},
+	}
+	defer func(format string) { *statsFormat = format }(*statsFormat)
+	for _, tt := range tests {
+		*statsFormat = tt.format
+		funcStats = nil
+		lintFiles(filename)
+		var buf bytes.Buffer
+		if err := printStats(&buf); err != nil {
+			t.Fatalf("printStats: %v", err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("-stats %s gives\n%s\nwant\n%s", tt.format, got, tt.want)
+		}
+	}
+}
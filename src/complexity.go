@@ -0,0 +1,196 @@
+// Copyright (c) 2013 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// FuncStats holds the complexity metrics of a function declaration.
+type FuncStats struct {
+	Position   token.Position // position of the function name
+	Name       string         // name of the function, qualified by its receiver type for methods
+	Cyclomatic int            // number of linearly independent paths through the function
+	Cognitive  int            // how hard the control flow is to follow, penalizing nesting
+}
+
+// lintComplexity computes the complexity metrics of each function declaration.
+// It passes them to the Linter's FuncStats callback, and complains about
+// functions whose cyclomatic or cognitive complexity is above the limits.
+func (f *file) lintComplexity() {
+	l := f.pkg.linter
+	enabled := f.pkg.enabled("complexity")
+	if !enabled && l.FuncStats == nil {
+		return
+	}
+	maxCyclomatic := limit(l.MaxCyclomatic, 15)
+	maxCognitive := limit(l.MaxCognitive, 15)
+	for _, decl := range f.f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		stats := FuncStats{
+			Position:   f.fset.Position(fn.Name.Pos()),
+			Name:       fn.Name.Name,
+			Cyclomatic: cyclomaticComplexity(fn),
+			Cognitive:  cognitiveComplexity(fn),
+		}
+		thing := "func"
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			thing = "method"
+			stats.Name = receiverType(fn) + "." + stats.Name
+		}
+		if l.FuncStats != nil {
+			l.FuncStats(stats)
+		}
+		if !enabled {
+			continue
+		}
+		if stats.Cyclomatic > maxCyclomatic {
+			f.errorf(fn.Name, 0.8, category("complexity"), "%s %s has a cyclomatic complexity of %d, more than %d; consider splitting it", thing, stats.Name, stats.Cyclomatic, maxCyclomatic)
+		}
+		if stats.Cognitive > maxCognitive {
+			f.errorf(fn.Name, 0.8, category("complexity"), "%s %s has a cognitive complexity of %d, more than %d; consider reducing its nesting or splitting it", thing, stats.Name, stats.Cognitive, maxCognitive)
+		}
+	}
+}
+
+// cyclomaticComplexity returns the cyclomatic complexity of fn:
+// one plus the number of branch points, counting each case clause
+// and each && and || operator. Function literals are included.
+func cyclomaticComplexity(fn *ast.FuncDecl) int {
+	n := 1
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if node.List != nil {
+				n++
+			}
+		case *ast.CommClause:
+			if node.Comm != nil {
+				n++
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.LAND || node.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// cognitiveComplexity returns the cognitive complexity of fn, following
+// the definition by G. Ann Campbell: each break in the linear flow costs one,
+// plus one for each level of nesting it is in; else branches, labeled jumps
+// and each run of alike && or || operators cost one regardless of nesting.
+func cognitiveComplexity(fn *ast.FuncDecl) int {
+	c := &cognitive{}
+	c.walk(fn.Body, 0)
+	return c.score
+}
+
+type cognitive struct {
+	score int
+}
+
+// walk adds the complexity of n to c.score, where n is nested at the given level.
+func (c *cognitive) walk(n ast.Node, nesting int) {
+	switch n := n.(type) {
+	case nil:
+		return
+	case *ast.IfStmt:
+		c.score += 1 + nesting
+		c.ifStmt(n, nesting)
+		return
+	case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+		c.score += 1 + nesting
+		c.children(n, nesting, true)
+		return
+	case *ast.FuncLit:
+		c.walk(n.Body, nesting+1)
+		return
+	case *ast.BranchStmt:
+		if n.Label != nil || n.Tok == token.GOTO {
+			c.score++
+		}
+		return
+	case *ast.BinaryExpr:
+		if n.Op == token.LAND || n.Op == token.LOR {
+			c.logical(n, nesting)
+			return
+		}
+	}
+	c.children(n, nesting, false)
+}
+
+// ifStmt walks the parts of the if statement n, whose own cost has been counted.
+func (c *cognitive) ifStmt(n *ast.IfStmt, nesting int) {
+	c.walk(n.Init, nesting)
+	c.walk(n.Cond, nesting)
+	c.walk(n.Body, nesting+1)
+	switch els := n.Else.(type) {
+	case *ast.IfStmt:
+		c.score++
+		c.ifStmt(els, nesting)
+	case *ast.BlockStmt:
+		c.score++
+		c.walk(els, nesting+1)
+	}
+}
+
+// children walks the direct children of n. If nest is set,
+// blocks and clauses among them are one level deeper than n.
+func (c *cognitive) children(n ast.Node, nesting int, nest bool) {
+	ast.Inspect(n, func(child ast.Node) bool {
+		if child == n {
+			return true
+		}
+		if child == nil {
+			return false
+		}
+		level := nesting
+		if nest {
+			if _, ok := child.(*ast.BlockStmt); ok {
+				level++
+			}
+		}
+		c.walk(child, level)
+		return false
+	})
+}
+
+// logical adds the cost of the sequence of && and || operators rooted at n:
+// one for each run of alike operators, so that a && b && c costs one and
+// a && b || c costs two.
+func (c *cognitive) logical(n *ast.BinaryExpr, nesting int) {
+	var ops []token.Token
+	var operands []ast.Expr
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			flatten(b.X)
+			ops = append(ops, b.Op)
+			flatten(b.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(n)
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			c.score++
+		}
+	}
+	for _, e := range operands {
+		c.walk(e, nesting)
+	}
+}
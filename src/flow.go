@@ -0,0 +1,860 @@
+// Copyright (c) 2013 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package lint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// This file implements a small flow-sensitive walk over function bodies.
+// It follows the structure of the syntax tree instead of building a full
+// control-flow graph, and records simple facts about local variables
+// (and selector chains rooted at them) as it goes, such as whether a
+// variable is known to be nil or how long it is. It is deliberately
+// conservative: any assignment to a variable forgets what was known about
+// it, and a function containing a goto statement is not walked at all.
+
+// nilness describes what is known about whether a value is nil.
+type nilness int
+
+const (
+	nilUnknown nilness = iota
+	isNil              // the value is nil
+	notNil             // the value is not nil
+	maybeNil           // the value is nil on some path reaching this point
+)
+
+// A varKey identifies a local variable: by its object when type information
+// is available, so that a variable shadowing another one is kept apart from it,
+// and by its name otherwise.
+type varKey struct {
+	obj  types.Object
+	name string
+}
+
+// An exprKey identifies an expression the walk keeps track of:
+// a local variable, or a selector chain rooted at one.
+type exprKey struct {
+	root varKey
+	path string // the selectors following the variable, such as ".a.b"
+}
+
+// String returns the expression as it is written in the source.
+func (k exprKey) String() string {
+	return k.root.name + k.path
+}
+
+// A fact records what is known about an expression at some point in a function body.
+type fact struct {
+	root    varKey   // the variable the expression is rooted at
+	nilness nilness  // whether the expression is nil
+	minLen  int      // a lower bound on the length of the expression
+	maxLen  int      // an upper bound on the length of the expression, if hasMax
+	hasMax  bool     // whether maxLen is known
+	origin  ast.Node // the check or assignment that established the fact
+}
+
+// known reports whether the fact records anything at all.
+func (fc fact) known() bool {
+	return fc.nilness != nilUnknown || fc.minLen > 0 || fc.hasMax
+}
+
+// A flowState holds the facts known at some point in a function body,
+// keyed by the expression they describe.
+// A nil flowState means the point is unreachable.
+type flowState map[exprKey]fact
+
+func (st flowState) clone() flowState {
+	c := make(flowState, len(st))
+	for k, v := range st {
+		c[k] = v
+	}
+	return c
+}
+
+// kill forgets the facts about expressions rooted at the variable v.
+func (st flowState) kill(v varKey) {
+	for k, fc := range st {
+		if fc.root == v {
+			delete(st, k)
+		}
+	}
+}
+
+// killSelectors forgets the facts about selector chains,
+// whose values may be changed by any function call.
+func (st flowState) killSelectors() {
+	for k := range st {
+		if k.path != "" {
+			delete(st, k)
+		}
+	}
+}
+
+// join returns the facts that hold after two paths meet.
+func join(a, b flowState) flowState {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	st := make(flowState)
+	for k, fa := range a {
+		fb, ok := b[k]
+		if !ok {
+			continue
+		}
+		switch {
+		case fa.nilness == fb.nilness:
+		case fa.nilness == isNil || fa.nilness == maybeNil:
+			fa.nilness = maybeNil
+		case fb.nilness == isNil || fb.nilness == maybeNil:
+			fa.nilness, fa.origin = maybeNil, fb.origin
+		default:
+			fa.nilness = nilUnknown
+		}
+		if fb.minLen < fa.minLen {
+			fa.minLen = fb.minLen
+		}
+		if fb.maxLen > fa.maxLen {
+			fa.maxLen = fb.maxLen
+		}
+		fa.hasMax = fa.hasMax && fb.hasMax
+		if fa.known() {
+			st[k] = fa
+		}
+	}
+	return st
+}
+
+// flowKey returns the key under which facts about e are recorded.
+// It reports false if e is not an expression the walk keeps track of.
+func (w *flowWalker) flowKey(e ast.Expr) (key exprKey, ok bool) {
+	switch e := e.(type) {
+	case *ast.ParenExpr:
+		return w.flowKey(e.X)
+	case *ast.Ident:
+		if e.Name == "_" || e.Name == "nil" || w.untracked[e.Name] {
+			return exprKey{}, false
+		}
+		v := w.varKey(e)
+		if v.obj != nil {
+			// Only local variables are tracked; anything else may be changed
+			// behind our back by a function call.
+			obj, ok := v.obj.(*types.Var)
+			if !ok || obj.Parent() == obj.Pkg().Scope() {
+				return exprKey{}, false
+			}
+		}
+		return exprKey{root: v}, true
+	case *ast.SelectorExpr:
+		key, ok := w.flowKey(e.X)
+		if !ok {
+			return exprKey{}, false
+		}
+		key.path += "." + e.Sel.Name
+		return key, true
+	}
+	return exprKey{}, false
+}
+
+// varKey returns the key of the variable id refers to.
+func (w *flowWalker) varKey(id *ast.Ident) varKey {
+	v := varKey{name: id.Name}
+	if info := w.f.pkg.typesInfo; info != nil {
+		v.obj = info.ObjectOf(id)
+	}
+	return v
+}
+
+// assume returns the facts that hold once cond is known to evaluate to truth.
+// The receiver is not modified.
+func (w *flowWalker) assume(st flowState, cond ast.Expr, truth bool) flowState {
+	st = st.clone()
+	w.learn(st, cond, truth)
+	return st
+}
+
+func (w *flowWalker) learn(st flowState, cond ast.Expr, truth bool) {
+	switch c := cond.(type) {
+	case *ast.ParenExpr:
+		w.learn(st, c.X, truth)
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			w.learn(st, c.X, !truth)
+		}
+	case *ast.BinaryExpr:
+		switch c.Op {
+		case token.LAND:
+			if truth {
+				w.learn(st, c.X, true)
+				w.learn(st, c.Y, true)
+			}
+		case token.LOR:
+			if !truth {
+				w.learn(st, c.X, false)
+				w.learn(st, c.Y, false)
+			}
+		case token.EQL, token.NEQ:
+			x := c.X
+			if isIdent(x, "nil") {
+				x = c.Y
+			} else if !isIdent(c.Y, "nil") {
+				w.learnLen(st, c, truth)
+				return
+			}
+			key, ok := w.flowKey(x)
+			if !ok {
+				return
+			}
+			fc := st[key]
+			n := notNil
+			if truth == (c.Op == token.EQL) {
+				n = isNil
+			}
+			if fc.nilness != n {
+				fc.root, fc.nilness, fc.origin = key.root, n, c
+			}
+			if n == isNil {
+				// A nil slice has no elements.
+				fc.minLen, fc.maxLen, fc.hasMax = 0, 0, true
+			}
+			st[key] = fc
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			w.learnLen(st, c, truth)
+		}
+	}
+}
+
+// mirroredOps maps comparison operators to the operators that give the
+// same result with their operands swapped.
+var mirroredOps = map[token.Token]token.Token{
+	token.EQL: token.EQL,
+	token.NEQ: token.NEQ,
+	token.LSS: token.GTR,
+	token.LEQ: token.GEQ,
+	token.GTR: token.LSS,
+	token.GEQ: token.LEQ,
+}
+
+// negatedOps maps comparison operators to their negations.
+var negatedOps = map[token.Token]token.Token{
+	token.EQL: token.NEQ,
+	token.NEQ: token.EQL,
+	token.LSS: token.GEQ,
+	token.LEQ: token.GTR,
+	token.GTR: token.LEQ,
+	token.GEQ: token.LSS,
+}
+
+// learnLen records what a comparison of the form len(x) < n tells about the length of x.
+func (w *flowWalker) learnLen(st flowState, c *ast.BinaryExpr, truth bool) {
+	op, x, y := c.Op, c.X, c.Y
+	if !isLenCall(x) {
+		op, x, y = mirroredOps[op], y, x
+	}
+	if !isLenCall(x) {
+		return
+	}
+	if !truth {
+		op = negatedOps[op]
+	}
+	n, ok := w.f.constInt(y)
+	if !ok {
+		return
+	}
+	key, ok := w.flowKey(x.(*ast.CallExpr).Args[0])
+	if !ok {
+		return
+	}
+	lo, hi, hasHi := 0, 0, false
+	switch op {
+	case token.EQL:
+		lo, hi, hasHi = n, n, true
+	case token.NEQ:
+		if n == 0 {
+			lo = 1
+		}
+	case token.LSS:
+		hi, hasHi = n-1, true
+	case token.LEQ:
+		hi, hasHi = n, true
+	case token.GTR:
+		lo = n + 1
+	case token.GEQ:
+		lo = n
+	}
+	// The origin of a fact is the check that last narrowed it.
+	// A check that narrows nothing, such as len(x) != 3, leaves the facts alone.
+	fc := st[key]
+	narrowed := false
+	if lo > fc.minLen {
+		fc.minLen, fc.origin, narrowed = lo, c, true
+	}
+	if hasHi && (!fc.hasMax || hi < fc.maxLen) {
+		fc.maxLen, fc.hasMax, fc.origin, narrowed = hi, true, c, true
+	}
+	if !narrowed {
+		return
+	}
+	fc.root = key.root
+	if fc.minLen > 0 {
+		fc.nilness = notNil
+	}
+	st[key] = fc
+}
+
+// learnAssign records what is known about the variable assigned by x = v.
+func (w *flowWalker) learnAssign(st flowState, x, v ast.Expr) {
+	key, ok := w.flowKey(x)
+	if !ok {
+		return
+	}
+	fc := fact{root: key.root, origin: v}
+	switch v := v.(type) {
+	case *ast.Ident:
+		if v.Name != "nil" {
+			return
+		}
+		fc.nilness, fc.hasMax = isNil, true
+	case *ast.CallExpr:
+		// make([]T, n)
+		if !isIdent(v.Fun, "make") || len(v.Args) < 2 {
+			return
+		}
+		if !isSlice(w.f.pkg.typeOf(v)) {
+			return
+		}
+		n, ok := w.f.constInt(v.Args[1])
+		if !ok {
+			return
+		}
+		fc.nilness, fc.minLen, fc.maxLen, fc.hasMax = notNil, n, n, true
+	case *ast.CompositeLit:
+		if !isSlice(w.f.pkg.typeOf(v)) {
+			return
+		}
+		for _, elt := range v.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return
+			}
+		}
+		fc.nilness, fc.minLen, fc.maxLen, fc.hasMax = notNil, len(v.Elts), len(v.Elts), true
+	default:
+		return
+	}
+	st[key] = fc
+}
+
+// isSlice reports whether typ is a slice type.
+func isSlice(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+	_, ok := typ.Underlying().(*types.Slice)
+	return ok
+}
+
+// decide reports whether the facts in st determine the value of cond,
+// and if so, what it is and which check or assignment established it.
+func (w *flowWalker) decide(st flowState, cond ast.Expr) (truth bool, origin ast.Node, ok bool) {
+	switch c := cond.(type) {
+	case *ast.ParenExpr:
+		return w.decide(st, c.X)
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			truth, origin, ok = w.decide(st, c.X)
+			return !truth, origin, ok
+		}
+	case *ast.BinaryExpr:
+		switch c.Op {
+		case token.LAND, token.LOR:
+			// The result is decided by either operand having the value
+			// that short-circuits the operator, or by both operands.
+			short := c.Op == token.LOR
+			tx, ox, okx := w.decide(st, c.X)
+			if okx && tx == short {
+				return short, ox, true
+			}
+			ty, oy, oky := w.decide(w.assume(st, c.X, !short), c.Y)
+			if oky && ty == short {
+				return short, oy, true
+			}
+			if okx && oky {
+				return !short, ox, true
+			}
+		case token.EQL, token.NEQ:
+			x := c.X
+			if isIdent(x, "nil") {
+				x = c.Y
+			} else if !isIdent(c.Y, "nil") {
+				return w.decideLen(st, c)
+			}
+			key, ok := w.flowKey(x)
+			if !ok {
+				return false, nil, false
+			}
+			fc := st[key]
+			if fc.nilness != isNil && fc.nilness != notNil {
+				return false, nil, false
+			}
+			return (fc.nilness == isNil) == (c.Op == token.EQL), fc.origin, true
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return w.decideLen(st, c)
+		}
+	}
+	return false, nil, false
+}
+
+// decideLen is like decide, for comparisons of the form len(x) < n.
+func (w *flowWalker) decideLen(st flowState, c *ast.BinaryExpr) (truth bool, origin ast.Node, ok bool) {
+	op, x, y := c.Op, c.X, c.Y
+	if !isLenCall(x) {
+		op, x, y = mirroredOps[op], y, x
+	}
+	if !isLenCall(x) {
+		return false, nil, false
+	}
+	n, ok := w.f.constInt(y)
+	if !ok {
+		return false, nil, false
+	}
+	key, ok := w.flowKey(x.(*ast.CallExpr).Args[0])
+	if !ok {
+		return false, nil, false
+	}
+	fc, ok := st[key]
+	if !ok {
+		return false, nil, false
+	}
+	// What the facts say about len(x) compared to n.
+	above := fc.minLen > n              // len(x) > n
+	below := fc.hasMax && fc.maxLen < n // len(x) < n
+	equal := fc.hasMax && fc.minLen == n && fc.maxLen == n
+	switch op {
+	case token.EQL:
+		if equal || above || below {
+			return equal, fc.origin, true
+		}
+	case token.NEQ:
+		if equal || above || below {
+			return !equal, fc.origin, true
+		}
+	case token.LSS:
+		if below || fc.minLen >= n {
+			return below, fc.origin, true
+		}
+	case token.LEQ:
+		if fc.hasMax && fc.maxLen <= n || above {
+			return !above, fc.origin, true
+		}
+	case token.GTR:
+		if above || fc.hasMax && fc.maxLen <= n {
+			return above, fc.origin, true
+		}
+	case token.GEQ:
+		if fc.minLen >= n || below {
+			return !below, fc.origin, true
+		}
+	}
+	return false, nil, false
+}
+
+// isTerminating reports whether control never reaches the end of list,
+// because it ends in a return, a branch statement or a call such as panic.
+func (f *file) isTerminating(list []ast.Stmt) bool {
+	if len(list) == 0 {
+		return false
+	}
+	switch s := list[len(list)-1].(type) {
+	case *ast.ReturnStmt, *ast.BranchStmt:
+		return true
+	case *ast.ExprStmt:
+		return f.isTerminatingCall(s.X)
+	case *ast.BlockStmt:
+		return f.isTerminating(s.List)
+	}
+	return false
+}
+
+// isLenCall reports whether e is a call of the form len(x).
+func isLenCall(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	return ok && isIdent(call.Fun, "len") && len(call.Args) == 1
+}
+
+// constInt returns the value of e if it is a constant that fits in an int.
+func (f *file) constInt(e ast.Expr) (int, bool) {
+	if f.pkg.typesInfo != nil {
+		if tv, ok := f.pkg.typesInfo.Types[e]; ok && tv.Value != nil {
+			n, exact := constant.Int64Val(constant.ToInt(tv.Value))
+			return int(n), exact && int64(int(n)) == n
+		}
+	}
+	// Without type information, only an integer literal will do.
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.Atoi(lit.Value)
+	return n, err == nil
+}
+
+// A flowWalker walks the statements of a function body in execution order,
+// keeping track of the facts that hold before each one.
+type flowWalker struct {
+	f *file
+
+	// expr, if non-nil, is called for each expression evaluated by the body,
+	// along with the facts that hold just before it is evaluated.
+	// The operands of && and || are passed separately.
+	// The bodies of function literals are walked on their own,
+	// and should not be inspected by expr.
+	expr func(e ast.Expr, st flowState)
+
+	// branch, if non-nil, is called for each if statement,
+	// along with the facts that hold just before its condition is evaluated.
+	branch func(s *ast.IfStmt, st flowState)
+
+	// untracked holds the names of variables that are assigned within
+	// function literals or whose address is taken. Nothing is recorded about them.
+	untracked map[string]bool
+}
+
+// walkFlow runs w over the body of every function in the file.
+func (f *file) walkFlow(w *flowWalker) {
+	w.f = f
+	f.walk(func(n ast.Node) bool {
+		var body *ast.BlockStmt
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			body = fn.Body
+		case *ast.FuncLit:
+			body = fn.Body
+		}
+		if body == nil || containsGoto(body) {
+			return true
+		}
+		w.untracked = untrackedNames(body)
+		w.block(body.List, make(flowState))
+		return true
+	})
+}
+
+// containsGoto reports whether there is a goto statement in body.
+func containsGoto(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if b, ok := n.(*ast.BranchStmt); ok && b.Tok == token.GOTO {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// untrackedNames returns the names of the variables in body that are
+// assigned within a function literal or have their address taken.
+func untrackedNames(body *ast.BlockStmt) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				if id, ok := rootIdent(n.X); ok {
+					names[id.Name] = true
+				}
+			}
+		case *ast.FuncLit:
+			for _, id := range assignedIdents(n.Body) {
+				names[id.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// rootIdent returns the variable at the root of a selector chain such as a.b.c.
+func rootIdent(e ast.Expr) (*ast.Ident, bool) {
+	for {
+		switch x := e.(type) {
+		case *ast.Ident:
+			return x, true
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.SelectorExpr:
+			e = x.X
+		case *ast.IndexExpr:
+			e = x.X
+		case *ast.StarExpr:
+			e = x.X
+		default:
+			return nil, false
+		}
+	}
+}
+
+// assignedIdents returns the variables assigned anywhere within n.
+func assignedIdents(n ast.Node) []*ast.Ident {
+	var ids []*ast.Ident
+	add := func(exprs ...ast.Expr) {
+		for _, e := range exprs {
+			if id, ok := rootIdent(e); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			add(n.Lhs...)
+		case *ast.IncDecStmt:
+			add(n.X)
+		case *ast.RangeStmt:
+			add(n.Key, n.Value)
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				ids = append(ids, id)
+			}
+		}
+		return true
+	})
+	return ids
+}
+
+// containsCall reports whether n contains a function call outside of function literals.
+func containsCall(n ast.Node) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.CallExpr:
+			found = true
+		case *ast.FuncLit:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// block walks a list of statements, and returns the facts that hold after it.
+func (w *flowWalker) block(list []ast.Stmt, st flowState) flowState {
+	for _, s := range list {
+		if st == nil {
+			// The rest of the block is unreachable.
+			break
+		}
+		st = w.stmt(s, st)
+	}
+	return st
+}
+
+// stmt walks a single statement, and returns the facts that hold after it.
+// The incoming flowState may be modified.
+func (w *flowWalker) stmt(s ast.Stmt, st flowState) flowState {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		return w.block(s.List, st)
+	case *ast.LabeledStmt:
+		return w.stmt(s.Stmt, st)
+	case *ast.IfStmt:
+		if s.Init != nil {
+			st = w.stmt(s.Init, st)
+		}
+		if w.branch != nil {
+			w.branch(s, st)
+		}
+		w.visit(st, s.Cond)
+		then := w.block(s.Body.List, w.assume(st, s.Cond, true))
+		els := w.assume(st, s.Cond, false)
+		if s.Else != nil {
+			els = w.stmt(s.Else, els)
+		}
+		// Don't let a branch that is never taken dilute what is known.
+		if truth, _, ok := w.decide(st, s.Cond); ok {
+			if truth {
+				return then
+			}
+			return els
+		}
+		return join(then, els)
+	case *ast.ForStmt:
+		if s.Init != nil {
+			st = w.stmt(s.Init, st)
+		}
+		w.forget(st, s)
+		w.visit(st, s.Cond)
+		body := st
+		if s.Cond != nil {
+			body = w.assume(st, s.Cond, true)
+		}
+		body = w.block(s.Body.List, body.clone())
+		if s.Post != nil && body != nil {
+			w.stmt(s.Post, body)
+		}
+		return st
+	case *ast.RangeStmt:
+		w.visit(st, s.X)
+		w.forget(st, s)
+		w.block(s.Body.List, st.clone())
+		return st
+	case *ast.SwitchStmt:
+		if s.Init != nil {
+			st = w.stmt(s.Init, st)
+		}
+		w.visit(st, s.Tag)
+		w.forget(st, s)
+		for _, c := range s.Body.List {
+			c := c.(*ast.CaseClause)
+			w.visit(st, c.List...)
+			w.block(c.Body, st.clone())
+		}
+		return st
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil {
+			st = w.stmt(s.Init, st)
+		}
+		st = w.stmt(s.Assign, st)
+		w.forget(st, s)
+		for _, c := range s.Body.List {
+			w.block(c.(*ast.CaseClause).Body, st.clone())
+		}
+		return st
+	case *ast.SelectStmt:
+		w.forget(st, s)
+		for _, c := range s.Body.List {
+			c := c.(*ast.CommClause)
+			cst := st.clone()
+			if c.Comm != nil {
+				cst = w.stmt(c.Comm, cst)
+			}
+			w.block(c.Body, cst)
+		}
+		return st
+	case *ast.ReturnStmt:
+		w.visit(st, s.Results...)
+		return nil
+	case *ast.BranchStmt:
+		return nil
+	case *ast.ExprStmt:
+		w.visit(st, s.X)
+		if w.f.isTerminatingCall(s.X) {
+			return nil
+		}
+	case *ast.AssignStmt:
+		w.visit(st, s.Rhs...)
+		w.visit(st, s.Lhs...)
+		w.forget(st, s)
+		if len(s.Lhs) == len(s.Rhs) && (s.Tok == token.ASSIGN || s.Tok == token.DEFINE) {
+			for i, lhs := range s.Lhs {
+				w.learnAssign(st, lhs, s.Rhs[i])
+			}
+		}
+		return st
+	case *ast.DeclStmt:
+		if gd, ok := s.Decl.(*ast.GenDecl); ok {
+			for _, spec := range gd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					w.visit(st, vs.Values...)
+				}
+			}
+		}
+		w.forget(st, s)
+		if gd, ok := s.Decl.(*ast.GenDecl); ok {
+			for _, spec := range gd.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) == len(vs.Values) {
+					for i, id := range vs.Names {
+						w.learnAssign(st, id, vs.Values[i])
+					}
+				}
+			}
+		}
+		return st
+	case *ast.IncDecStmt:
+		w.visit(st, s.X)
+	case *ast.SendStmt:
+		w.visit(st, s.Chan, s.Value)
+	case *ast.GoStmt:
+		w.visit(st, s.Call)
+	case *ast.DeferStmt:
+		w.visit(st, s.Call)
+	}
+	w.forget(st, s)
+	return st
+}
+
+// forget removes the facts that may be invalidated by executing s.
+func (w *flowWalker) forget(st flowState, s ast.Stmt) {
+	for _, id := range assignedIdents(s) {
+		st.kill(w.varKey(id))
+	}
+	if containsCall(s) {
+		st.killSelectors()
+	}
+}
+
+// visit passes each of the expressions to w.expr.
+func (w *flowWalker) visit(st flowState, exprs ...ast.Expr) {
+	for _, e := range exprs {
+		if e == nil || w.expr == nil {
+			continue
+		}
+		if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			w.visit(st, b.X)
+			w.visit(w.assume(st, b.X, b.Op == token.LAND), b.Y)
+			continue
+		}
+		w.expr(e, st)
+	}
+}
+
+// isTerminatingCall reports whether e is a call that never returns,
+// such as a call to panic, os.Exit or the Fatal method of a *testing.T.
+func (f *file) isTerminatingCall(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	if isIdent(call.Fun, "panic") || isPkgDot(call.Fun, "os", "Exit") {
+		return true
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if isIdent(sel.X, "log") {
+		switch sel.Sel.Name {
+		case "Fatal", "Fatalf", "Fatalln", "Panic", "Panicf", "Panicln":
+			return true
+		}
+	}
+	// The methods of testing.T, testing.B and testing.TB that stop the test
+	// are promoted from the unexported testing.common, or declared by TB.
+	if fn, ok := f.pkg.typesInfo.Uses[sel.Sel].(*types.Func); ok && fn.Pkg() != nil && fn.Pkg().Path() == "testing" {
+		switch fn.Name() {
+		case "Fatal", "Fatalf", "FailNow", "Skip", "Skipf", "SkipNow":
+			return true
+		}
+	}
+	return false
+}
+
+// inspectFlowExpr calls fn for each node in e, skipping the bodies of function literals.
+func inspectFlowExpr(e ast.Expr, fn func(ast.Node) bool) {
+	ast.Inspect(e, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+		return fn(n)
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2013 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"go/token"
+	"sort"
+)
+
+// This file implements the rules about formatting: a general one comparing
+// the file with the output of gofmt, and more specific ones for the
+// differences that are easy to miss when reading the code.
+
+// lintFormat examines the formatting of the file. The differences that
+// lintFormatTokens reports are left out of the region that lintGofmt
+// reports, so that each is reported once.
+func (f *file) lintFormat() {
+	fixes := f.formatTokenFixes()
+	start, end := f.lintGofmt(fixes)
+	f.lintFormatTokens(fixes, start, end)
+}
+
+// A formatFix is a problem found by lintFormatTokens, fixed by removing
+// the source between the byte offsets start and end.
+type formatFix struct {
+	start, end int
+	text       string
+}
+
+// lintGofmt compares the file with its formatting by gofmt, once the fixes
+// are applied. It complains about the first region that differs, offering
+// the formatted text of the region as a fix, and returns the byte offsets
+// of the region, or -1 if there is none.
+func (f *file) lintGofmt(fixes []formatFix) (start, end int) {
+	if !f.pkg.enabled("gofmt") {
+		return -1, -1
+	}
+	src := make([]byte, 0, len(f.src))
+	last := 0
+	for _, fx := range fixes {
+		src = append(src, f.src[last:fx.start]...)
+		last = fx.end
+	}
+	src = append(src, f.src[last:]...)
+	formatted, err := format.Source(src)
+	if err != nil || bytes.Equal(formatted, src) {
+		return -1, -1
+	}
+	a, b := bytes.SplitAfter(src, []byte("\n")), bytes.SplitAfter(formatted, []byte("\n"))
+	first := 0
+	for first < len(a) && first < len(b) && bytes.Equal(a[first], b[first]) {
+		first++
+	}
+	// Find the nearest lines from which the file and its formatting agree again,
+	// so that only the first differing region is reported.
+	endA, endB := len(a), len(b)
+search:
+	for d := 1; d <= endA+endB-2*first; d++ {
+		for i := first; i <= first+d && i <= len(a); i++ {
+			if j := first + d - (i - first); j <= len(b) && linesAgree(a[i:], b[j:]) {
+				endA, endB = i, j
+				break search
+			}
+		}
+	}
+	offset := len(bytes.Join(a[:first], nil))
+	old := bytes.Join(a[first:endA], nil)
+	repl := bytes.Join(b[first:endB], nil)
+
+	// Map the offsets in src back to the file. The region starts after
+	// any text removed just before it, and ends before any removed just after it.
+	orig := func(x int, after bool) int {
+		removed := 0
+		for _, fx := range fixes {
+			if at := fx.start - removed; at > x || at == x && !after {
+				break
+			}
+			removed += fx.end - fx.start
+		}
+		return x + removed
+	}
+	start, end = orig(offset, true), orig(offset+len(old), false)
+	if end < start {
+		end = start
+	}
+
+	pos := f.posAt(start)
+	p := f.pkg.errorfAt(f.fset.Position(pos), 0.8, link("https://golang.org/cmd/gofmt/"), category("format"),
+		"file is not gofmt-ed from this line; run gofmt")
+	p.Edits = []Edit{f.edit(pos, f.posAt(end), string(repl))}
+	return start, end
+}
+
+// linesAgree reports whether a and b start with the same few lines,
+// or with the same lines up to their common end.
+func linesAgree(a, b [][]byte) bool {
+	const n = 3
+	if len(a) != len(b) && (len(a) < n || len(b) < n) {
+		return false
+	}
+	for i := 0; i < n && i < len(a); i++ {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// lintFormatTokens complains about the problems found by formatTokenFixes,
+// offering to remove the text as a fix. The fixes within the region between
+// the byte offsets start and end are left to the fix of that region.
+func (f *file) lintFormatTokens(fixes []formatFix, start, end int) {
+	for _, fx := range fixes {
+		pos := f.posAt(fx.start)
+		p := f.pkg.errorfAt(f.fset.Position(pos), 0.9, category("format"), "%s", fx.text)
+		if start < end && start <= fx.start && fx.end <= end {
+			continue
+		}
+		p.Edits = []Edit{f.edit(pos, f.posAt(fx.end), "")}
+	}
+}
+
+// formatTokenFixes examines the tokens of the file. It returns, in the
+// order of the file, the explicit semicolons at the end of a line and the
+// runs of more than one blank line, which gofmt removes.
+func (f *file) formatTokenFixes() []formatFix {
+	semicolons, blankLines := f.pkg.enabled("semicolons"), f.pkg.enabled("blank-lines")
+	if !semicolons && !blankLines {
+		return nil
+	}
+	// Scan a copy of the file, so that the scanner doesn't add
+	// line information to the file set of the package.
+	fset := token.NewFileSet()
+	tf := fset.AddFile(f.filename, -1, len(f.src))
+	var s scanner.Scanner
+	s.Init(tf, f.src, nil, scanner.ScanComments)
+
+	var fixes []formatFix
+	var semi token.Pos // explicit semicolon waiting for the next token
+	prevEnd := token.NoPos
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.SEMICOLON && lit == "\n" {
+			// Inserted automatically; not in the source.
+			continue
+		}
+		line := tf.Line(pos)
+		if blankLines && prevEnd.IsValid() && line-tf.Line(prevEnd) > 2 {
+			// Keep one of the blank lines and remove the others.
+			fixes = append(fixes, formatFix{
+				start: tf.Offset(tf.LineStart(tf.Line(prevEnd) + 2)),
+				end:   tf.Offset(tf.LineStart(line)),
+				text:  fmt.Sprintf("%d consecutive blank lines; use at most one", line-tf.Line(prevEnd)-1),
+			})
+		}
+		if lit == "" {
+			lit = tok.String()
+		}
+		prevEnd = pos + token.Pos(len(lit))
+
+		if tok == token.COMMENT {
+			continue
+		}
+		if semi.IsValid() && (tok == token.EOF || tok == token.RBRACE || tf.Line(semi) < line) {
+			start := tf.Offset(semi)
+			fixes = append(fixes, formatFix{start: start, end: start + 1, text: "unnecessary semicolon at end of statement"})
+		}
+		semi = token.NoPos
+		if tok == token.EOF {
+			break
+		}
+		if semicolons && tok == token.SEMICOLON {
+			semi = pos
+		}
+	}
+	// A semicolon is found only at the next token, after any blank lines.
+	sort.Slice(fixes, func(i, j int) bool { return fixes[i].start < fixes[j].start })
+	return fixes
+}
+
+// posAt returns the position of the byte at offset in the file.
+func (f *file) posAt(offset int) token.Pos {
+	return f.fset.File(f.f.Pos()).Pos(offset)
+}
@@ -12,10 +12,15 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
 	"go/parser"
 	"go/printer"
 	"go/token"
 	"go/types"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -24,13 +29,63 @@ import (
 	"unicode/utf8"
 
 	"golang.org/x/tools/go/ast/astutil"
-	"golang.org/x/tools/go/gcexportdata"
 )
 
 const styleGuideBase = "https://golang.org/wiki/CodeReviewComments"
 
 // A Linter lints Go source code.
+// The zero value is ready to use.
 type Linter struct {
+	// IgnoredErrors lists functions and methods whose error results may be
+	// dropped without complaint, in addition to those in defaultIgnoredErrors.
+	// Names are of the form reported by types.Func.FullName,
+	// such as "fmt.Println" or "(*bytes.Buffer).Write". A method may also be
+	// named after the static type of the receiver, such as "(hash.Hash).Write".
+	IgnoredErrors []string
+
+	// ErrorConstructors lists functions that create an error from a message
+	// passed as their first argument, in addition to errors.New and fmt.Errorf.
+	// Names are of the same form as IgnoredErrors.
+	ErrorConstructors []string
+
+	// MaxParams and MaxResults are the numbers of parameters and results
+	// above which a function's signature is reported as hard to use.
+	// MaxSameTypedParams is the length of a run of adjacent bool or integer
+	// parameters of one type above which callers are likely to mix them up.
+	// Zero means the defaults of 5, 2 and 3 respectively.
+	MaxParams          int
+	MaxResults         int
+	MaxSameTypedParams int
+
+	// MaxCyclomatic and MaxCognitive are the cyclomatic and cognitive
+	// complexities above which a function is reported as too complex.
+	// Zero means the default of 15 for both.
+	MaxCyclomatic int
+	MaxCognitive  int
+
+	// MaxNakedReturnLines is the length in lines of a function
+	// above which its naked returns are reported. Zero means the default of 10.
+	MaxNakedReturnLines int
+
+	// AllowedNumbers lists the numeric literals that may appear in conditions,
+	// arithmetic and make calls without being named, in addition to 0, 1, -1 and 2.
+	// They are written as in Go source, such as "10", "-0x7f" or "0.5";
+	// a negative number is only allowed if it is listed with its sign.
+	AllowedNumbers []string
+
+	// ReportTypeErrors makes the Linter report the errors found while
+	// type-checking a package as problems, followed by a summary of the
+	// rules whose results may be incomplete for lack of type information.
+	ReportTypeErrors bool
+
+	// FuncStats, if non-nil, is called with the complexity metrics
+	// of every function declaration in the linted files.
+	FuncStats func(FuncStats)
+
+	// Disabled holds the names of rules that should not be run,
+	// such as "nil-deref". The rules that can be disabled are
+	// listed in the README.
+	Disabled map[string]bool
 }
 
 // Problem represents a problem in some source code.
@@ -45,6 +100,563 @@ type Problem struct {
 	// If the problem has a suggested fix (the minority case),
 	// ReplacementLine is a full replacement for the relevant line of the source file.
 	ReplacementLine string
+
+	// Related lists other places in the source involved in the problem,
+	// such as the earlier check that makes a branch unreachable.
+	Related []RelatedPosition
+
+	// Edits, if non-empty, is a suggested fix that can't be expressed as a
+	// ReplacementLine, such as one that inserts lines or touches several places.
+	Edits []Edit
+}
+
+// An Edit is a change to the source: the text between Pos and End is replaced by New.
+type Edit struct {
+	Pos, End token.Position // the range of the text to replace; Pos == End for an insertion
+	New      string         // the replacement text
+}
+
+// edit returns an Edit replacing the text between pos and end with text.
+func (f *file) edit(pos, end token.Pos, text string) Edit {
+	return Edit{Pos: f.fset.Position(pos), End: f.fset.Position(end), New: text}
+}
+
+// RelatedPosition is a secondary position of a Problem.
+type RelatedPosition struct {
+	Position token.Position // position in source file
+	Text     string         // the prose that describes the role of this position
+}
+
+// related returns a RelatedPosition for n, described by the formatted text.
+// n is typically an ast.Node, but may also be a types.Object declared in the file.
+func (f *file) related(n interface{ Pos() token.Pos }, format string, args ...interface{}) RelatedPosition {
+	pos := f.fset.Position(n.Pos())
+	if pos.Filename == "" {
+		pos.Filename = f.filename
+	}
+	return RelatedPosition{Position: pos, Text: fmt.Sprintf(format, args...)}
+}
+
+func (p *Problem) String() string {
+	if p.Link != "" {
+		return p.Text + "\n\n" + p.Link
+	}
+	return p.Text
+}
+
+type byPosition []Problem
+
+func (p byPosition) Len() int      { return len(p) }
+func (p byPosition) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p byPosition) Less(i, j int) bool {
+	pi, pj := p[i].Position, p[j].Position
+
+	if pi.Filename != pj.Filename {
+		return pi.Filename < pj.Filename
+	}
+	if pi.Line != pj.Line {
+		return pi.Line < pj.Line
+	}
+	if pi.Column != pj.Column {
+		return pi.Column < pj.Column
+	}
+
+	return p[i].Text < p[j].Text
+}
+
+// Lint lints src.
+func (l *Linter) Lint(filename string, src []byte) ([]Problem, error) {
+	return l.LintFiles(map[string][]byte{filename: src})
+}
+
+// LintFiles lints a set of files of a single package.
+// The argument is a map of filename to source.
+func (l *Linter) LintFiles(files map[string][]byte) ([]Problem, error) {
+	pkg := &pkg{
+		linter: l,
+		fset:   token.NewFileSet(),
+		files:  make(map[string]*file),
+	}
+	var pkgName string
+	for filename, src := range files {
+		if isGenerated(src) {
+			continue // See issue #239
+		}
+		f, err := parser.ParseFile(pkg.fset, filename, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkgName == "" {
+			pkgName = f.Name.Name
+		} else if f.Name.Name != pkgName {
+			return nil, fmt.Errorf("%s is in package %s, not %s", filename, f.Name.Name, pkgName)
+		}
+		pkg.files[filename] = &file{
+			pkg:      pkg,
+			f:        f,
+			fset:     pkg.fset,
+			src:      src,
+			filename: filename,
+		}
+	}
+	if len(pkg.files) == 0 {
+		return nil, nil
+	}
+	return pkg.lint(), nil
+}
+
+var (
+	genHdr = []byte("// Code generated ")
+	genFtr = []byte(" DO NOT EDIT.")
+)
+
+// isGenerated reports whether the source file is generated code
+// according the rules from https://golang.org/s/generatedcode.
+func isGenerated(src []byte) bool {
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		b := sc.Bytes()
+		if bytes.HasPrefix(b, genHdr) && bytes.HasSuffix(b, genFtr) && len(b) >= len(genHdr)+len(genFtr) {
+			return true
+		}
+	}
+	return false
+}
+
+// pkg represents a package being linted.
+type pkg struct {
+	linter *Linter
+	fset   *token.FileSet
+	files  map[string]*file
+
+	typesPkg   *types.Package
+	typesInfo  *types.Info
+	typeErrors []types.Error // errors found while type-checking
+
+	// sortable is the set of types in the package that implement sort.Interface.
+	sortable map[string]bool
+	// main is whether this is a "main" package.
+	main bool
+	// mod is the module containing the package, once moduleRead is set.
+	mod        *module
+	moduleRead bool
+
+	problems []Problem
+}
+
+func (p *pkg) lint() []Problem {
+	if err := p.typeCheck(); err != nil {
+		/* TODO(dsymonds): Consider reporting these errors when golint operates on entire packages.
+		if e, ok := err.(types.Error); ok {
+			pos := p.fset.Position(e.Pos)
+			conf := 1.0
+			if strings.Contains(e.Msg, "can't find import: ") {
+				// Golint is probably being run in a context that doesn't support
+				// typechecking (e.g. package files aren't found), so don't warn about it.
+				conf = 0
+			}
+			if conf > 0 {
+				p.errorfAt(pos, conf, category("typechecking"), e.Msg)
+			}
+
+			// TODO(dsymonds): Abort if !e.Soft?
+		}
+		*/
+	}
+
+	p.scanSortable()
+	p.main = p.isMain()
+
+	for _, f := range p.files {
+		f.lint()
+	}
+	p.lintPackageComments()
+	p.lintReceivers()
+	p.lintShadowedNames()
+	p.lintUnused()
+	p.lintTypeErrors()
+	p.lintImportPaths()
+
+	sort.Sort(byPosition(p.problems))
+
+	return p.problems
+}
+
+// file represents a file being linted.
+type file struct {
+	pkg      *pkg
+	f        *ast.File
+	fset     *token.FileSet
+	src      []byte
+	filename string
+}
+
+func (f *file) isTest() bool { return strings.HasSuffix(f.filename, "_test.go") }
+
+func (f *file) lint() {
+	f.lintPackageComment()
+	f.lintImports()
+	f.lintBlankImports()
+	f.lintExported()
+	f.lintNames()
+	f.lintElses()
+	f.lintRanges()
+	f.lintErrorf()
+	f.lintErrors()
+	f.lintErrorStrings()
+	f.lintReceiverNames()
+	f.lintIncDec()
+	f.lintErrorReturn()
+	f.lintUnexportedReturn()
+	f.lintTimeNames()
+	f.lintContextKeyTypes()
+	f.lintContextArgs()
+	f.lintNilDeref()
+	f.lintIgnoredErrors()
+	f.lintRedundantNilChecks()
+	f.lintDuplicateConditions()
+	f.lintSliceBounds()
+	f.lintDeadBranches()
+	f.lintErrorPrefixes()
+	f.lintErrorConstructors()
+	f.lintErrorWrapping()
+	f.lintUnexportedParams()
+	f.lintSignatures()
+	f.lintComplexity()
+	f.lintGetters()
+	f.lintNakedReturns()
+	f.lintShadowing()
+	f.lintSilentNilInput()
+	f.lintFormat()
+	f.lintMagicNumbers()
+}
+
+type link string
+type category string
+
+// The variadic arguments may start with link and category types,
+// and must end with a format string and any arguments.
+// It returns the new Problem.
+func (f *file) errorf(n ast.Node, confidence float64, args ...interface{}) *Problem {
+	pos := f.fset.Position(n.Pos())
+	if pos.Filename == "" {
+		pos.Filename = f.filename
+	}
+	return f.pkg.errorfAt(pos, confidence, args...)
+}
+
+func (p *pkg) errorfAt(pos token.Position, confidence float64, args ...interface{}) *Problem {
+	problem := Problem{
+		Position:   pos,
+		Confidence: confidence,
+	}
+	if pos.Filename != "" {
+		// The file might not exist in our mapping if a //line directive was encountered.
+		if f, ok := p.files[pos.Filename]; ok {
+			problem.LineText = srcLine(f.src, pos)
+		}
+	}
+
+argLoop:
+	for len(args) > 1 { // always leave at least the format string in args
+		switch v := args[0].(type) {
+		case link:
+			problem.Link = string(v)
+		case category:
+			problem.Category = string(v)
+		default:
+			break argLoop
+		}
+		args = args[1:]
+	}
+
+	problem.Text = fmt.Sprintf(args[0].(string), args[1:]...)
+
+	p.problems = append(p.problems, problem)
+	return &p.problems[len(p.problems)-1]
+}
+
+// newImporter returns the importer used to type-check a package.
+// Export data is not installed with recent Go releases, so imported
+// packages are type-checked from source.
+var newImporter = func(fset *token.FileSet) types.ImporterFrom {
+	return importer.ForCompiler(fset, "source", nil).(types.ImporterFrom)
+}
+
+func (p *pkg) typeCheck() error {
+	config := &types.Config{
+		// By setting an error reporter, the type checker does as much work as possible.
+		// The errors are kept for lintTypeErrors.
+		Error: func(err error) {
+			if e, ok := err.(types.Error); ok {
+				p.typeErrors = append(p.typeErrors, e)
+			}
+		},
+		Importer: newImporter(p.fset),
+	}
+	info := &types.Info{
+		Types:  make(map[ast.Expr]types.TypeAndValue),
+		Defs:   make(map[*ast.Ident]types.Object),
+		Uses:   make(map[*ast.Ident]types.Object),
+		Scopes: make(map[ast.Node]*types.Scope),
+	}
+	var anyFile *file
+	var astFiles []*ast.File
+	for _, f := range p.files {
+		anyFile = f
+		astFiles = append(astFiles, f.f)
+	}
+	pkg, err := config.Check(anyFile.f.Name.Name, p.fset, astFiles, info)
+	// Remember the typechecking info, even if config.Check failed,
+	// since we will get partial information.
+	p.typesPkg = pkg
+	p.typesInfo = info
+	return err
+}
+
+func (p *pkg) typeOf(expr ast.Expr) types.Type {
+	if p.typesInfo == nil {
+		return nil
+	}
+	return p.typesInfo.TypeOf(expr)
+}
+
+func (p *pkg) isNamedType(typ types.Type, importPath, name string) bool {
+	n, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	tn := n.Obj()
+	return tn != nil && tn.Pkg() != nil && tn.Pkg().Path() == importPath && tn.Name() == name
+}
+
+// scopeOf returns the tightest scope encompassing id.
+func (p *pkg) scopeOf(id *ast.Ident) *types.Scope {
+	var scope *types.Scope
+	if obj := p.typesInfo.ObjectOf(id); obj != nil {
+		scope = obj.Parent()
+	}
+	if scope == p.typesPkg.Scope() {
+		// We were given a top-level identifier.
+		// Use the file-level scope instead of the package-level scope.
+		pos := id.Pos()
+		for _, f := range p.files {
+			if f.f.Pos() <= pos && pos < f.f.End() {
+				scope = p.typesInfo.Scopes[f.f]
+				break
+			}
+		}
+	}
+	return scope
+}
+
+func (p *pkg) scanSortable() {
+	p.sortable = make(map[string]bool)
+
+	// bitfield for which methods exist on each type.
+	const (
+		Len = 1 << iota
+		Less
+		Swap
+	)
+	nmap := map[string]int{"Len": Len, "Less": Less, "Swap": Swap}
+	has := make(map[string]int)
+	for _, f := range p.files {
+		f.walk(func(n ast.Node) bool {
+			fn, ok := n.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				return true
+			}
+			// TODO(dsymonds): We could check the signature to be more precise.
+			recv := receiverType(fn)
+			if i, ok := nmap[fn.Name.Name]; ok {
+				has[recv] |= i
+			}
+			return false
+		})
+	}
+	for typ, ms := range has {
+		if ms == Len|Less|Swap {
+			p.sortable[typ] = true
+		}
+	}
+}
+
+func (p *pkg) isMain() bool {
+	for _, f := range p.files {
+		if f.isMain() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *file) isMain() bool {
+	if f.f.Name.Name == "main" {
+		return true
+	}
+	return false
+}
+
+// lintPackageComment checks package comments. It complains if
+// there is no package comment, or if it is not of the right form.
+// This has a notable false positive in that a package comment
+// could rightfully appear in a different file of the same package,
+// but that's not easy to fix since this linter is file-oriented.
+func (f *file) lintPackageComment() {
+	if f.isTest() {
+		return
+	}
+
+	const ref = styleGuideBase + "#package-comments"
+	prefix := "Package " + f.f.Name.Name + " "
+
+	// Look for a detached package comment.
+	// First, scan for the last comment that occurs before the "package" keyword.
+	var lastCG *ast.CommentGroup
+	for _, cg := range f.f.Comments {
+		if cg.Pos() > f.f.Package {
+			// Gone past "package" keyword.
+			break
+		}
+		lastCG = cg
+	}
+	if lastCG != nil && strings.HasPrefix(lastCG.Text(), prefix) {
+		endPos := f.fset.Position(lastCG.End())
+		pkgPos := f.fset.Position(f.f.Package)
+		if endPos.Line+1 < pkgPos.Line {
+			// There isn't a great place to anchor this error;
+			// the start of the blank lines between the doc and the package statement
+			// is at least pointing at the location of the problem.
+			pos := token.Position{
+				Filename: endPos.Filename,
+				// Offset not set; it is non-trivial, and doesn't appear to be needed.
+				Line:   endPos.Line + 1,
+				Column: 1,
+			}
+			f.pkg.errorfAt(pos, 0.9, link(ref), category("comments"), "package comment is detached; there should be no blank lines between it and the package statement")
+			return
+		}
+	}
+
+	// With the package-comments rule, a missing comment and one not of
+	// the standard form are reported once for the package by lintPackageComments.
+	perFile := !f.pkg.enabled("package-comments")
+	if f.f.Doc == nil {
+		if perFile {
+			f.errorf(f.f, 0.2, link(ref), category("comments"), "should have a package comment, unless it's in another file for this package")
+		}
+		return
+	}
+	s := f.f.Doc.Text()
+	if ts := strings.TrimLeft(s, " \t"); ts != s {
+		f.errorf(f.f.Doc, 1, link(ref), category("comments"), "package comment should not have leading space")
+		s = ts
+	}
+	// Only non-main packages need to keep to this form.
+	if perFile && !f.pkg.main && !strings.HasPrefix(s, prefix) {
+		f.errorf(f.f.Doc, 1, link(ref), category("comments"), `package comment should be of the form "%s..."`, prefix)
+	}
+}
+
+// lintBlankImports complains if a non-main package has blank imports that are
+// not documented.
+func (f *file) lintBlankImports() {
+	// In package main and in tests, we don't complain about blank imports.
+	if f.pkg.main || f.isTest() {
+		return
+	}
+
+	// The first element of each contiguous group of blank imports should have
+	// an explanatory comment of some kind.
+	for i, imp := range f.f.Imports {
+		pos := f.fset.Position(imp.Pos())
+
+		if !isBlank(imp.Name) {
+			continue // Ignore non-blank imports.
+		}
+		if i > 0 {
+			prev := f.f.Imports[i-1]
+			prevPos := f.fset.Position(prev.Pos())
+			if isBlank(prev.Name) && prevPos.Line+1 == pos.Line {
+				continue // A subsequent blank in a group.
+			}
+		}
+
+		// This is the first blank import of a group.
+		if imp.Doc == nil && imp.Comment == nil {
+			ref := ""
+			f.errorf(imp, 1, link(ref), category("imports"), "a blank import should be only in a main or test package, or have a comment justifying it")
+		}
+	}
+}
+
+// lintImports examines import blocks.
+func (f *file) lintImports() {
+	for i, is := range f.f.Imports {
+		_ = i
+		if is.Name != nil && is.Name.Name == "." && !f.isTest() {
+			f.errorf(is, 1, link(styleGuideBase+"#import-dot"), category("imports"), "should not use dot imports")
+		}
+
+	}
+}
+
+const docCommentsLink = styleGuideBase + "#doc-comments"
+
+// lintExported examines the exported names.
+// It complains if any required doc comments are missing,
+// or if they are not of the right form. The exact rules are in
+// lintFuncDoc, lintTypeDoc and lintValueSpecDoc; this function
+// also tracks the GenDecl structure being traversed to permit
+// doc comments for constants to be on top of the const block.
+// It also complains if the names stutter when combined with
+// the package name.
+func (f *file) lintExported() {
+	if f.isTest() {
+		return
+	}
+
+	var lastGen *ast.GenDecl // last GenDecl entered.
+
+	// Set of GenDecls that have already had missing comments flagged.
+	genDeclMissingComments := make(map[*ast.GenDecl]bool)
+
+	f.walk(func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.GenDecl:
+			if v.Tok == token.IMPORT {
+				return false
+			}
+			// token.CONST, token.TYPE or token.VAR
+			lastGen = v
+			return true
+		case *ast.FuncDecl:
+			f.lintFuncDoc(v)
+			if v.Recv == nil {
+				// Only check for stutter on functions, not methods.
+				// Method names are not used package-qualified.
+				f.checkStutter(v.Name, "func")
+			}
+			// Don't proceed inside funcs.
+			return false
+		case *ast.TypeSpec:
+			// inside a GenDecl, which usually has the doc
+			doc := v.Doc
+			if doc == nil {
+				doc = lastGen.Doc
+			}
+			f.lintTypeDoc(v, doc)
+			f.checkStutter(v.Name, "type")
+			// Don't proceed inside types.
+			return false
+		case *ast.ValueSpec:
+			f.lintValueSpecDoc(v, lastGen, genDeclMissingComments)
+			return false
+		}
+		return true
+	})
 }
 
 var (
@@ -72,6 +684,263 @@ func isInTopLevel(f *ast.File, ident *ast.Ident) bool {
 	return true
 }
 
+// lintNames examines all names in the file.
+// It complains if any use underscores or incorrect known initialisms.
+func (f *file) lintNames() {
+	// Package names need slightly different handling than other names.
+	if strings.Contains(f.f.Name.Name, "_") && !strings.HasSuffix(f.f.Name.Name, "_test") {
+		f.errorf(f.f, 1, link("http://golang.org/doc/effective_go.html#package-names"), category("naming"), "don't use an underscore in package name")
+	}
+	if anyCapsRE.MatchString(f.f.Name.Name) {
+		f.errorf(f.f, 1, link("http://golang.org/doc/effective_go.html#package-names"), category("mixed-caps"), "don't use MixedCaps in package name; %s should be %s", f.f.Name.Name, strings.ToLower(f.f.Name.Name))
+	}
+
+	check := func(id *ast.Ident, thing string) {
+		if id.Name == "_" {
+			return
+		}
+		if knownNameExceptions[id.Name] {
+			return
+		}
+
+		// Handle two common styles from other languages that don't belong in Go.
+		if len(id.Name) >= 5 && allCapsRE.MatchString(id.Name) && strings.Contains(id.Name, "_") {
+			capCount := 0
+			for _, c := range id.Name {
+				if 'A' <= c && c <= 'Z' {
+					capCount++
+				}
+			}
+			if capCount >= 2 {
+				f.errorf(id, 0.8, link(styleGuideBase+"#mixed-caps"), category("naming"), "don't use ALL_CAPS in Go names; use CamelCase")
+				return
+			}
+		}
+		if thing == "const" || (thing == "var" && isInTopLevel(f.f, id)) {
+			if len(id.Name) > 2 && id.Name[0] == 'k' && id.Name[1] >= 'A' && id.Name[1] <= 'Z' {
+				should := string(id.Name[1]+'a'-'A') + id.Name[2:]
+				f.errorf(id, 0.8, link(styleGuideBase+"#mixed-caps"), category("naming"), "don't use leading k in Go names; %s %s should be %s", thing, id.Name, should)
+			}
+		}
+
+		should := lintName(id.Name)
+		if id.Name == should {
+			return
+		}
+
+		if len(id.Name) > 2 && strings.Contains(id.Name[1:], "_") {
+			f.errorf(id, 0.9, link("http://golang.org/doc/effective_go.html#mixed-caps"), category("naming"), "don't use underscores in Go names; %s %s should be %s", thing, id.Name, should)
+			return
+		}
+		f.errorf(id, 0.8, link(styleGuideBase+"#initialisms"), category("naming"), "%s %s should be %s", thing, id.Name, should)
+	}
+	checkList := func(fl *ast.FieldList, thing string) {
+		if fl == nil {
+			return
+		}
+		for _, f := range fl.List {
+			for _, id := range f.Names {
+				check(id, thing)
+			}
+		}
+	}
+	f.walk(func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.AssignStmt:
+			if v.Tok == token.ASSIGN {
+				return true
+			}
+			for _, exp := range v.Lhs {
+				if id, ok := exp.(*ast.Ident); ok {
+					check(id, "var")
+				}
+			}
+		case *ast.FuncDecl:
+			if f.isTest() && (strings.HasPrefix(v.Name.Name, "Example") || strings.HasPrefix(v.Name.Name, "Test") || strings.HasPrefix(v.Name.Name, "Benchmark")) {
+				return true
+			}
+
+			thing := "func"
+			if v.Recv != nil {
+				thing = "method"
+			}
+
+			// Exclude naming warnings for functions that are exported to C but
+			// not exported in the Go API.
+			// See https://github.com/golang/lint/issues/144.
+			if ast.IsExported(v.Name.Name) || !isCgoExported(v) {
+				check(v.Name, thing)
+			}
+
+			checkList(v.Type.Params, thing+" parameter")
+			checkList(v.Type.Results, thing+" result")
+		case *ast.GenDecl:
+			if v.Tok == token.IMPORT {
+				return true
+			}
+			var thing string
+			switch v.Tok {
+			case token.CONST:
+				thing = "const"
+			case token.TYPE:
+				thing = "type"
+			case token.VAR:
+				thing = "var"
+			}
+			for _, spec := range v.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					check(s.Name, thing)
+				case *ast.ValueSpec:
+					for _, id := range s.Names {
+						check(id, thing)
+					}
+				}
+			}
+		case *ast.InterfaceType:
+			// Do not check interface method names.
+			// They are often constrainted by the method names of concrete types.
+			for _, x := range v.Methods.List {
+				ft, ok := x.Type.(*ast.FuncType)
+				if !ok { // might be an embedded interface name
+					continue
+				}
+				checkList(ft.Params, "interface method parameter")
+				checkList(ft.Results, "interface method result")
+			}
+		case *ast.RangeStmt:
+			if v.Tok == token.ASSIGN {
+				return true
+			}
+			if id, ok := v.Key.(*ast.Ident); ok {
+				check(id, "range var")
+			}
+			if id, ok := v.Value.(*ast.Ident); ok {
+				check(id, "range var")
+			}
+		case *ast.StructType:
+			for _, f := range v.Fields.List {
+				for _, id := range f.Names {
+					check(id, "struct field")
+				}
+			}
+		}
+		return true
+	})
+}
+
+// lintName returns a different name if it should be different.
+func lintName(name string) (should string) {
+	// Fast path for simple cases: "_" and all lowercase.
+	if name == "_" {
+		return name
+	}
+	allLower := true
+	for _, r := range name {
+		if !unicode.IsLower(r) {
+			allLower = false
+			break
+		}
+	}
+	if allLower {
+		return name
+	}
+
+	// Split camelCase at any lower->upper transition, and split on underscores.
+	// Check each word for common initialisms.
+	runes := []rune(name)
+	w, i := 0, 0 // index of start of word, scan
+	for i+1 <= len(runes) {
+		eow := false // whether we hit the end of a word
+		if i+1 == len(runes) {
+			eow = true
+		} else if runes[i+1] == '_' {
+			// underscore; shift the remainder forward over any run of underscores
+			eow = true
+			n := 1
+			for i+n+1 < len(runes) && runes[i+n+1] == '_' {
+				n++
+			}
+
+			// Leave at most one underscore if the underscore is between two digits
+			if i+n+1 < len(runes) && unicode.IsDigit(runes[i]) && unicode.IsDigit(runes[i+n+1]) {
+				n--
+			}
+
+			copy(runes[i+1:], runes[i+n+1:])
+			runes = runes[:len(runes)-n]
+		} else if unicode.IsLower(runes[i]) && !unicode.IsLower(runes[i+1]) {
+			// lower->non-lower
+			eow = true
+		}
+		i++
+		if !eow {
+			continue
+		}
+
+		// [w,i) is a word.
+		word := string(runes[w:i])
+		if u := strings.ToUpper(word); commonInitialisms[u] {
+			// Keep consistent case, which is lowercase only at the start.
+			if w == 0 && unicode.IsLower(runes[w]) {
+				u = strings.ToLower(u)
+			}
+			// All the common initialisms are ASCII,
+			// so we can replace the bytes exactly.
+			copy(runes[w:], []rune(u))
+		} else if w > 0 && strings.ToLower(word) == word {
+			// already all lowercase, and not the first word, so uppercase the first character.
+			runes[w] = unicode.ToUpper(runes[w])
+		}
+		w = i
+	}
+	return string(runes)
+}
+
+// commonInitialisms is a set of common initialisms.
+// Only add entries that are highly unlikely to be non-initialisms.
+// For instance, "ID" is fine (Freudian code is rare), but "AND" is not.
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
 // lintTypeDoc examines the doc comment on a type.
 // It complains if they are missing from an exported type,
 // or if they are not of the standard form.
@@ -83,6 +952,7 @@ func (f *file) lintTypeDoc(t *ast.TypeSpec, doc *ast.CommentGroup) {
 		f.errorf(t, 1, link(docCommentsLink), category("comments"), "exported type %v should have comment or be unexported", t.Name)
 		return
 	}
+	f.lintDocText(doc, "type", t.Name.Name)
 
 	s := doc.Text()
 	articles := [...]string{"A", "An", "The"}
@@ -138,6 +1008,8 @@ func (f *file) lintFuncDoc(fn *ast.FuncDecl) {
 		f.errorf(fn, 1, link(docCommentsLink), category("comments"), "exported %s %s should have comment or be unexported", kind, name)
 		return
 	}
+	f.lintDocText(fn.Doc, kind, name)
+	f.lintDocResults(fn, kind, name)
 	s := fn.Doc.Text()
 	prefix := fn.Name.Name + " "
 	if !strings.HasPrefix(s, prefix) {
@@ -174,6 +1046,13 @@ func (f *file) lintValueSpecDoc(vs *ast.ValueSpec, gd *ast.GenDecl, genDeclMissi
 		if genDeclMissingComments[gd] {
 			return
 		}
+		if kind == "var" && gd.Lparen.IsValid() && f.isErrorBlock(gd) {
+			// A group of sentinel errors is best described as a whole.
+			p := f.errorf(gd, 1, link(docCommentsLink), category("comments"), "exported error variables in this block should have a comment on the block")
+			p.Edits = []Edit{f.edit(gd.Pos(), gd.Pos(), "// Errors returned by package "+f.f.Name.Name+".\n")}
+			genDeclMissingComments[gd] = true
+			return
+		}
 		block := ""
 		if kind == "const" && gd.Lparen.IsValid() {
 			block = " (or a comment on this block)"
@@ -192,34 +1071,3220 @@ func (f *file) lintValueSpecDoc(vs *ast.ValueSpec, gd *ast.GenDecl, genDeclMissi
 	if doc == nil {
 		doc = gd.Doc
 	}
+	f.lintDocText(doc, kind, name)
 	prefix := name + " "
 	if !strings.HasPrefix(doc.Text(), prefix) {
 		f.errorf(doc, 1, link(docCommentsLink), category("comments"), `comment on exported %s %s should be of the form "%s..."`, kind, name, prefix)
 	}
 }
 
-func (f *file) checkStutter(id *ast.Ident, thing string) {
-	pkg, name := f.f.Name.Name, id.Name
-	if !ast.IsExported(name) {
-		// unexported name
-		return
+// isErrorBlock reports whether every variable declared by gd
+// is initialized by a call to an error constructor such as errors.New.
+func (f *file) isErrorBlock(gd *ast.GenDecl) bool {
+	for _, spec := range gd.Specs {
+		vs, ok := spec.(*ast.ValueSpec)
+		if !ok || len(vs.Values) == 0 {
+			return false
+		}
+		for _, v := range vs.Values {
+			if call, ok := v.(*ast.CallExpr); !ok || !f.isErrorConstructor(call) {
+				return false
+			}
+		}
 	}
-	// A name stutters if the package name is a strict prefix
-	// and the next character of the name starts a new word.
-	if len(name) <= len(pkg) {
-		// name is too short to stutter.
-		// This permits the name to be the same as the package name.
-		return
+	return true
+}
+
+// isErrorConstructor reports whether call creates a new error from a message,
+// as errors.New and fmt.Errorf do.
+func (f *file) isErrorConstructor(call *ast.CallExpr) bool {
+	if fn := f.callee(call); fn != nil {
+		return f.pkg.isErrorConstructor(fn)
 	}
-	if !strings.EqualFold(pkg, name[:len(pkg)]) {
-		return
+	// Without type information, go by the names of the standard packages.
+	return isPkgDot(call.Fun, "errors", "New") || isPkgDot(call.Fun, "fmt", "Errorf")
+}
+
+// isErrorConstructor reports whether fn is errors.New, fmt.Errorf
+// or one of the constructors configured in the Linter.
+func (p *pkg) isErrorConstructor(fn *types.Func) bool {
+	name := fn.FullName()
+	if name == "errors.New" || name == "fmt.Errorf" {
+		return true
+	}
+	for _, s := range p.linter.ErrorConstructors {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lintDocText examines the text of the doc comment on an exported name.
+// It complains if the comment is not a full sentence, if it does no more
+// than repeat the name, or if it ends in an empty line.
+// kind describes what is documented, such as "type" or "method".
+func (f *file) lintDocText(doc *ast.CommentGroup, kind, name string) {
+	text := strings.TrimSpace(doc.Text())
+	lines := strings.Split(text, "\n")
+	last := lines[len(lines)-1]
+	// Trailing preformatted text, such as an example, needs no period.
+	preformatted := strings.HasPrefix(last, " ") || strings.HasPrefix(last, "\t")
+	if f.pkg.enabled("doc-period") && text != "" && !preformatted {
+		end := strings.TrimRight(text, `)"'`)
+		if r, _ := utf8.DecodeLastRuneInString(end); r != '.' && r != '!' && r != '?' {
+			p := f.errorf(doc, 0.8, link(docCommentsLink), category("comments"), "comment on exported %s %s should be a full sentence ending in a period", kind, name)
+			// Add the period to the last line comment that has text.
+			for i := len(doc.List) - 1; i >= 0; i-- {
+				c := doc.List[i]
+				if !strings.HasPrefix(c.Text, "//") {
+					break
+				}
+				if strings.TrimSpace(c.Text) != "//" {
+					p.Edits = []Edit{f.edit(c.End(), c.End(), ".")}
+					break
+				}
+			}
+		}
+	}
+
+	if f.pkg.enabled("doc-repeats-name") {
+		short := name
+		if i := strings.LastIndex(short, "."); i >= 0 {
+			short = short[i+1:]
+		}
+		// The comment must mention the name, so that an empty one isn't reported.
+		repeats := false
+		for _, w := range strings.Fields(strings.TrimRight(text, ".")) {
+			switch w {
+			case "A", "An", "The", "a", "an", "the":
+				continue
+			}
+			repeats = strings.EqualFold(w, short)
+			if !repeats {
+				break
+			}
+		}
+		if repeats {
+			f.errorf(doc, 0.9, link(docCommentsLink), category("comments"), "comment on exported %s %s only repeats its name; it should describe what %s is or does", kind, name, short)
+		}
+	}
+
+	if f.pkg.enabled("doc-trailing-empty-line") && len(doc.List) > 1 {
+		c := doc.List[len(doc.List)-1]
+		if strings.TrimSpace(c.Text) == "//" {
+			p := f.errorf(c, 0.9, link(docCommentsLink), category("comments"), "comment on exported %s %s should not end with an empty line", kind, name)
+			// Remove the whole line, leaving the end of the line before it
+			// free for the period that doc-period may add.
+			start := f.fset.File(c.Pos()).LineStart(f.fset.Position(c.Pos()).Line)
+			end := c.End()
+			if off := f.fset.Position(end).Offset; off < len(f.src) && f.src[off] == '\n' {
+				end++
+			}
+			p.Edits = []Edit{f.edit(start, end, "")}
+		}
+	}
+}
+
+// lintDocResults examines the doc comment on an exported function returning an error.
+// It complains if the comment doesn't mention each of the function's named results.
+func (f *file) lintDocResults(fn *ast.FuncDecl, kind, name string) {
+	if !f.pkg.enabled("doc-named-results") || fn.Type.Results == nil {
+		return
+	}
+	returnsError := false
+	var names []string
+	for _, field := range fn.Type.Results.List {
+		if isIdent(field.Type, "error") {
+			returnsError = true
+		}
+		for _, id := range field.Names {
+			if id.Name != "_" {
+				names = append(names, id.Name)
+			}
+		}
+	}
+	if !returnsError || len(names) == 0 {
+		return
+	}
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(fn.Doc.Text(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		words[w] = true
+	}
+	// An error result is mentioned by talking about the error it returns.
+	words["err"] = words["err"] || words["error"] || words["errors"]
+	var missing []string
+	for _, n := range names {
+		if !words[n] {
+			missing = append(missing, n)
+		}
+	}
+	switch len(missing) {
+	case 0:
+	case 1:
+		f.errorf(fn.Doc, 0.8, link(docCommentsLink), category("comments"), "comment on exported %s %s should describe its named result %s", kind, name, missing[0])
+	default:
+		f.errorf(fn.Doc, 0.8, link(docCommentsLink), category("comments"), "comment on exported %s %s should describe its named results %s", kind, name, strings.Join(missing, ", "))
+	}
+}
+
+// enabled reports whether the named rule should be run.
+func (p *pkg) enabled(rule string) bool {
+	return !p.linter.Disabled[rule]
+}
+
+func (f *file) checkStutter(id *ast.Ident, thing string) {
+	pkg, name := f.f.Name.Name, id.Name
+	if !ast.IsExported(name) {
+		// unexported name
+		return
+	}
+	// A name stutters if the package name is a strict prefix
+	// and the next character of the name starts a new word.
+	if len(name) <= len(pkg) {
+		// name is too short to stutter.
+		// This permits the name to be the same as the package name.
+		return
+	}
+	if !strings.EqualFold(pkg, name[:len(pkg)]) {
+		return
 	}
 	// We can assume the name is well-formed UTF-8.
 	// If the next rune after the package name is uppercase or an underscore
 	// the it's starting a new word and thus this name stutters.
 	rem := name[len(pkg):]
 	if next, _ := utf8.DecodeRuneInString(rem); next == '_' || unicode.IsUpper(next) {
-		f.errorf(id, 0.8, link(styleGuideBase+"#package-names"), category("naming"), "%s name will be used as %s.%s by other packages, and that stutters; consider calling this %s", thing, pkg, name, rem)
+		p := f.errorf(id, 0.8, link(styleGuideBase+"#package-names"), category("naming"), "%s name will be used as %s.%s by other packages, and that stutters; consider calling this %s", thing, pkg, name, rem)
+		p.Related = append(p.Related, f.related(f.f.Name, "package %s declared here", pkg))
+	}
+}
+
+// zeroLiteral is a set of ast.BasicLit values that are zero values.
+// It is not exhaustive.
+var zeroLiteral = map[string]bool{
+	"false": true, // bool
+	// runes
+	`'\x00'`: true,
+	`'\000'`: true,
+	// strings
+	`""`: true,
+	"``": true,
+	// numerics
+	"0":   true,
+	"0.":  true,
+	"0.0": true,
+	"0i":  true,
+}
+
+// lintElses examines else blocks. It complains about any else block whose if block ends in a return.
+func (f *file) lintElses() {
+	// We don't want to flag if { } else if { } else { } constructions.
+	// They will appear as an IfStmt whose Else field is also an IfStmt.
+	// Record such a node so we ignore it when we visit it.
+	ignore := make(map[*ast.IfStmt]bool)
+
+	f.walk(func(node ast.Node) bool {
+		ifStmt, ok := node.(*ast.IfStmt)
+		if !ok || ifStmt.Else == nil {
+			return true
+		}
+		if elseif, ok := ifStmt.Else.(*ast.IfStmt); ok {
+			ignore[elseif] = true
+			return true
+		}
+		if ignore[ifStmt] {
+			return true
+		}
+		if _, ok := ifStmt.Else.(*ast.BlockStmt); !ok {
+			// only care about elses without conditions
+			return true
+		}
+		if len(ifStmt.Body.List) == 0 {
+			return true
+		}
+		shortDecl := false // does the if statement have a ":=" initialization statement?
+		if ifStmt.Init != nil {
+			if as, ok := ifStmt.Init.(*ast.AssignStmt); ok && as.Tok == token.DEFINE {
+				shortDecl = true
+			}
+		}
+		lastStmt := ifStmt.Body.List[len(ifStmt.Body.List)-1]
+		if _, ok := lastStmt.(*ast.ReturnStmt); ok {
+			extra := ""
+			if shortDecl {
+				extra = " (move short variable declaration to its own line if necessary)"
+			}
+			f.errorf(ifStmt.Else, 1, link(styleGuideBase+"#indent-error-flow"), category("indent"), "if block ends with a return statement, so drop this else and outdent its block"+extra)
+		}
+		return true
+	})
+}
+
+// lintRanges examines range clauses. It complains about redundant constructions.
+func (f *file) lintRanges() {
+	f.walk(func(node ast.Node) bool {
+		rs, ok := node.(*ast.RangeStmt)
+		if !ok {
+			return true
+		}
+
+		if isIdent(rs.Key, "_") && (rs.Value == nil || isIdent(rs.Value, "_")) {
+			p := f.errorf(rs.Key, 1, category("range-loop"), "should omit values from range; this loop is equivalent to `for range ...`")
+
+			newRS := *rs // shallow copy
+			newRS.Value = nil
+			newRS.Key = nil
+			p.ReplacementLine = f.firstLineOf(&newRS, rs)
+
+			return true
+		}
+
+		if isIdent(rs.Value, "_") {
+			p := f.errorf(rs.Value, 1, category("range-loop"), "should omit 2nd value from range; this loop is equivalent to `for %s %s range ...`", f.render(rs.Key), rs.Tok)
+
+			newRS := *rs // shallow copy
+			newRS.Value = nil
+			p.ReplacementLine = f.firstLineOf(&newRS, rs)
+		}
+
+		return true
+	})
+}
+
+// lintErrorf examines errors.New and testing.Error calls. It complains if its only argument is an fmt.Sprintf invocation.
+func (f *file) lintErrorf() {
+	f.walk(func(node ast.Node) bool {
+		ce, ok := node.(*ast.CallExpr)
+		if !ok || len(ce.Args) != 1 {
+			return true
+		}
+		isErrorsNew := isPkgDot(ce.Fun, "errors", "New")
+		var isTestingError bool
+		se, ok := ce.Fun.(*ast.SelectorExpr)
+		if ok && se.Sel.Name == "Error" {
+			if typ := f.pkg.typeOf(se.X); typ != nil {
+				isTestingError = typ.String() == "*testing.T"
+			}
+		}
+		if !isErrorsNew && !isTestingError {
+			return true
+		}
+		if !f.imports("errors") {
+			return true
+		}
+		arg := ce.Args[0]
+		ce, ok = arg.(*ast.CallExpr)
+		if !ok || !isPkgDot(ce.Fun, "fmt", "Sprintf") {
+			return true
+		}
+		errorfPrefix := "fmt"
+		if isTestingError {
+			errorfPrefix = f.render(se.X)
+		}
+		p := f.errorf(node, 1, category("errors"), "should replace %s(fmt.Sprintf(...)) with %s.Errorf(...)", f.render(se), errorfPrefix)
+
+		m := f.srcLineWithMatch(ce, `^(.*)`+f.render(se)+`\(fmt\.Sprintf\((.*)\)\)(.*)$`)
+		if m != nil {
+			p.ReplacementLine = m[1] + errorfPrefix + ".Errorf(" + m[2] + ")" + m[3]
+		}
+
+		return true
+	})
+}
+
+// lintErrors examines global error vars. It complains if they aren't named in the standard way.
+func (f *file) lintErrors() {
+	for _, decl := range f.f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if len(spec.Names) != 1 || len(spec.Values) != 1 {
+				continue
+			}
+			ce, ok := spec.Values[0].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if !isPkgDot(ce.Fun, "errors", "New") && !isPkgDot(ce.Fun, "fmt", "Errorf") {
+				continue
+			}
+
+			id := spec.Names[0]
+			prefix := "err"
+			if id.IsExported() {
+				prefix = "Err"
+			}
+			if !strings.HasPrefix(id.Name, prefix) {
+				f.errorf(id, 0.9, category("naming"), "error var %s should have name of the form %sFoo", id.Name, prefix)
+			}
+		}
+	}
+}
+
+func lintErrorString(s string) (isClean bool, conf float64) {
+	const basicConfidence = 0.8
+	const capConfidence = basicConfidence - 0.2
+	first, firstN := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	if last == '.' || last == ':' || last == '!' || last == '\n' {
+		return false, basicConfidence
+	}
+	if unicode.IsUpper(first) {
+		// People use proper nouns and exported Go identifiers in error strings,
+		// so decrease the confidence of warnings for capitalization.
+		if len(s) <= firstN {
+			return false, capConfidence
+		}
+		// Flag strings starting with something that doesn't look like an initialism.
+		if second, _ := utf8.DecodeRuneInString(s[firstN:]); !unicode.IsUpper(second) {
+			return false, capConfidence
+		}
+	}
+	return true, 0
+}
+
+// lintErrorStrings examines error strings.
+// It complains if they are capitalized or end in punctuation or a newline.
+// With the error-strings rule, lintErrorConstructors checks them instead.
+func (f *file) lintErrorStrings() {
+	if f.pkg.enabled("error-strings") {
+		return
+	}
+	f.walk(func(node ast.Node) bool {
+		ce, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if !isPkgDot(ce.Fun, "errors", "New") && !isPkgDot(ce.Fun, "fmt", "Errorf") {
+			return true
+		}
+		if len(ce.Args) < 1 {
+			return true
+		}
+		str, ok := ce.Args[0].(*ast.BasicLit)
+		if !ok || str.Kind != token.STRING {
+			return true
+		}
+		s, _ := strconv.Unquote(str.Value) // can assume well-formed Go
+		if s == "" {
+			return true
+		}
+		clean, conf := lintErrorString(s)
+		if clean {
+			return true
+		}
+
+		f.errorf(str, conf, link(styleGuideBase+"#error-strings"), category("errors"),
+			"error strings should not be capitalized or end with punctuation or a newline")
+		return true
+	})
+}
+
+// lintReceiverNames examines receiver names. It complains about inconsistent
+// names used for the same type and names such as "this".
+func (f *file) lintReceiverNames() {
+	typeReceiver := map[string]string{}
+	f.walk(func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return true
+		}
+		names := fn.Recv.List[0].Names
+		if len(names) < 1 {
+			return true
+		}
+		name := names[0].Name
+		const ref = styleGuideBase + "#receiver-names"
+		if name == "_" {
+			f.errorf(n, 1, link(ref), category("naming"), `receiver name should not be an underscore, omit the name if it is unused`)
+			return true
+		}
+		if name == "this" || name == "self" {
+			f.errorf(n, 1, link(ref), category("naming"), `receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"`)
+			return true
+		}
+		recv := receiverType(fn)
+		if prev, ok := typeReceiver[recv]; ok && prev != name {
+			f.errorf(n, 1, link(ref), category("naming"), "receiver name %s should be consistent with previous receiver name %s for %s", name, prev, recv)
+			return true
+		}
+		typeReceiver[recv] = name
+		return true
+	})
+}
+
+// lintIncDec examines statements that increment or decrement a variable.
+// It complains if they don't use x++ or x--.
+func (f *file) lintIncDec() {
+	f.walk(func(n ast.Node) bool {
+		as, ok := n.(*ast.AssignStmt)
+		if !ok {
+			return true
+		}
+		if len(as.Lhs) != 1 {
+			return true
+		}
+		if !isOne(as.Rhs[0]) {
+			return true
+		}
+		var suffix string
+		switch as.Tok {
+		case token.ADD_ASSIGN:
+			suffix = "++"
+		case token.SUB_ASSIGN:
+			suffix = "--"
+		default:
+			return true
+		}
+		f.errorf(as, 0.8, category("unary-op"), "should replace %s with %s%s", f.render(as), f.render(as.Lhs[0]), suffix)
+		return true
+	})
+}
+
+// lintErrorReturn examines function declarations that return an error.
+// It complains if the error isn't the last parameter.
+func (f *file) lintErrorReturn() {
+	f.walk(func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Type.Results == nil {
+			return true
+		}
+		ret := fn.Type.Results.List
+		if len(ret) <= 1 {
+			return true
+		}
+		if isIdent(ret[len(ret)-1].Type, "error") {
+			return true
+		}
+		// An error return parameter should be the last parameter.
+		// Flag any error parameters found before the last.
+		for _, r := range ret[:len(ret)-1] {
+			if isIdent(r.Type, "error") {
+				f.errorf(fn, 0.9, category("arg-order"), "error should be the last type when returning multiple items")
+				break // only flag one
+			}
+		}
+		return true
+	})
+}
+
+// lintUnexportedReturn examines exported function declarations.
+// It complains if any return an unexported type.
+func (f *file) lintUnexportedReturn() {
+	f.walk(func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok {
+			return true
+		}
+		if fn.Type.Results == nil {
+			return false
+		}
+		if !fn.Name.IsExported() {
+			return false
+		}
+		thing := "func"
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			thing = "method"
+			if !ast.IsExported(receiverType(fn)) {
+				// Don't report exported methods of unexported types,
+				// such as private implementations of sort.Interface.
+				return false
+			}
+		}
+		for _, ret := range fn.Type.Results.List {
+			typ := f.pkg.typeOf(ret.Type)
+			if exportedType(typ) {
+				continue
+			}
+			f.errorf(ret.Type, 0.8, category("unexported-type-in-api"),
+				"exported %s %s returns unexported type %s, which can be annoying to use",
+				thing, fn.Name.Name, typ)
+			break // only flag one
+		}
+		return false
+	})
+}
+
+// exportedType reports whether typ is an exported type.
+// It is imprecise, and will err on the side of returning true,
+// such as for composite types.
+func exportedType(typ types.Type) bool {
+	switch T := typ.(type) {
+	case *types.Named:
+		// Builtin types have no package.
+		return T.Obj().Pkg() == nil || T.Obj().Exported()
+	case *types.Map:
+		return exportedType(T.Key()) && exportedType(T.Elem())
+	case interface {
+		Elem() types.Type
+	}: // array, slice, pointer, chan
+		return exportedType(T.Elem())
+	}
+	// Be conservative about other types, such as struct, interface, etc.
+	return true
+}
+
+// timeSuffixes is a list of name suffixes that imply a time unit.
+// This is not an exhaustive list.
+var timeSuffixes = []string{
+	"Sec", "Secs", "Seconds",
+	"Msec", "Msecs",
+	"Milli", "Millis", "Milliseconds",
+	"Usec", "Usecs", "Microseconds",
+	"MS", "Ms",
+}
+
+func (f *file) lintTimeNames() {
+	f.walk(func(node ast.Node) bool {
+		v, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for _, name := range v.Names {
+			origTyp := f.pkg.typeOf(name)
+			// Look for time.Duration or *time.Duration;
+			// the latter is common when using flag.Duration.
+			typ := origTyp
+			if pt, ok := typ.(*types.Pointer); ok {
+				typ = pt.Elem()
+			}
+			if !f.pkg.isNamedType(typ, "time", "Duration") {
+				continue
+			}
+			suffix := ""
+			for _, suf := range timeSuffixes {
+				if strings.HasSuffix(name.Name, suf) {
+					suffix = suf
+					break
+				}
+			}
+			if suffix == "" {
+				continue
+			}
+			f.errorf(v, 0.9, category("time"), "var %s is of type %v; don't use unit-specific suffix %q", name.Name, origTyp, suffix)
+		}
+		return true
+	})
+}
+
+// lintContextKeyTypes checks for call expressions to context.WithValue with
+// basic types used for the key argument.
+// See: https://golang.org/issue/17293
+func (f *file) lintContextKeyTypes() {
+	f.walk(func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			f.checkContextKeyType(node)
+		}
+
+		return true
+	})
+}
+
+// checkContextKeyType reports an error if the call expression calls
+// context.WithValue with a key argument of basic type.
+func (f *file) checkContextKeyType(x *ast.CallExpr) {
+	sel, ok := x.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "context" {
+		return
+	}
+	if sel.Sel.Name != "WithValue" {
+		return
+	}
+
+	// key is second argument to context.WithValue
+	if len(x.Args) != 3 {
+		return
+	}
+	key := f.pkg.typesInfo.Types[x.Args[1]]
+
+	if ktyp, ok := key.Type.(*types.Basic); ok && ktyp.Kind() != types.Invalid {
+		f.errorf(x, 1.0, category("context"), fmt.Sprintf("should not use basic type %s as key in context.WithValue", key.Type))
+	}
+}
+
+// lintContextArgs examines function declarations that contain an
+// argument with a type of context.Context
+// It complains if that argument isn't the first parameter.
+func (f *file) lintContextArgs() {
+	f.walk(func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || len(fn.Type.Params.List) <= 1 {
+			return true
+		}
+		// A context.Context should be the first parameter of a function.
+		// Flag any that show up after the first.
+		for _, arg := range fn.Type.Params.List[1:] {
+			if isPkgDot(arg.Type, "context", "Context") {
+				f.errorf(fn, 0.9, link("https://golang.org/pkg/context/"), category("arg-order"), "context.Context should be the first parameter of a function")
+				break // only flag one
+			}
+		}
+		return true
+	})
+}
+
+// containsComments returns whether the interval [start, end) contains any
+// comments without "// MATCH " prefix.
+func (f *file) containsComments(start, end token.Pos) bool {
+	for _, cgroup := range f.f.Comments {
+		comments := cgroup.List
+		if comments[0].Slash >= end {
+			// All comments starting with this group are after end pos.
+			return false
+		}
+		if comments[len(comments)-1].Slash < start {
+			// Comments group ends before start pos.
+			continue
+		}
+		for _, c := range comments {
+			if start <= c.Slash && c.Slash < end && !strings.HasPrefix(c.Text, "// MATCH ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// receiverType returns the named type of the method receiver, sans "*",
+// or "invalid-type" if fn.Recv is ill formed.
+func receiverType(fn *ast.FuncDecl) string {
+	switch e := fn.Recv.List[0].Type.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		if id, ok := e.X.(*ast.Ident); ok {
+			return id.Name
+		}
+	}
+	// The parser accepts much more than just the legal forms.
+	return "invalid-type"
+}
+
+func (f *file) walk(fn func(ast.Node) bool) {
+	ast.Walk(walker(fn), f.f)
+}
+
+func (f *file) render(x interface{}) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, f.fset, x); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+func (f *file) debugRender(x interface{}) string {
+	var buf bytes.Buffer
+	if err := ast.Fprint(&buf, f.fset, x, nil); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+// walker adapts a function to satisfy the ast.Visitor interface.
+// The function return whether the walk should proceed into the node's children.
+type walker func(ast.Node) bool
+
+func (w walker) Visit(node ast.Node) ast.Visitor {
+	if w(node) {
+		return w
+	}
+	return nil
+}
+
+func isIdent(expr ast.Expr, ident string) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == ident
+}
+
+// isBlank returns whether id is the blank identifier "_".
+// If id == nil, the answer is false.
+func isBlank(id *ast.Ident) bool { return id != nil && id.Name == "_" }
+
+func isPkgDot(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && isIdent(sel.X, pkg) && isIdent(sel.Sel, name)
+}
+
+func isOne(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "1"
+}
+
+func isCgoExported(f *ast.FuncDecl) bool {
+	if f.Recv != nil || f.Doc == nil {
+		return false
+	}
+
+	cgoExport := regexp.MustCompile(fmt.Sprintf("(?m)^//export %s$", regexp.QuoteMeta(f.Name.Name)))
+	for _, c := range f.Doc.List {
+		if cgoExport.MatchString(c.Text) {
+			return true
+		}
+	}
+	return false
+}
+
+var basicTypeKinds = map[types.BasicKind]string{
+	types.UntypedBool:    "bool",
+	types.UntypedInt:     "int",
+	types.UntypedRune:    "rune",
+	types.UntypedFloat:   "float64",
+	types.UntypedComplex: "complex128",
+	types.UntypedString:  "string",
+}
+
+// isUntypedConst reports whether expr is an untyped constant,
+// and indicates what its default type is.
+// scope may be nil.
+func (f *file) isUntypedConst(expr ast.Expr) (defType string, ok bool) {
+	// Re-evaluate expr outside of its context to see if it's untyped.
+	// (An expr evaluated within, for example, an assignment context will get the type of the LHS.)
+	exprStr := f.render(expr)
+	tv, err := types.Eval(f.fset, f.pkg.typesPkg, expr.Pos(), exprStr)
+	if err != nil {
+		return "", false
+	}
+	if b, ok := tv.Type.(*types.Basic); ok {
+		if dt, ok := basicTypeKinds[b.Kind()]; ok {
+			return dt, true
+		}
+	}
+
+	return "", false
+}
+
+// firstLineOf renders the given node and returns its first line.
+// It will also match the indentation of another node.
+func (f *file) firstLineOf(node, match ast.Node) string {
+	line := f.render(node)
+	if i := strings.Index(line, "\n"); i >= 0 {
+		line = line[:i]
+	}
+	return f.indentOf(match) + line
+}
+
+func (f *file) indentOf(node ast.Node) string {
+	line := srcLine(f.src, f.fset.Position(node.Pos()))
+	for i, r := range line {
+		switch r {
+		case ' ', '\t':
+		default:
+			return line[:i]
+		}
 	}
+	return line // unusual or empty line
 }
 
+func (f *file) srcLineWithMatch(node ast.Node, pattern string) (m []string) {
+	line := srcLine(f.src, f.fset.Position(node.Pos()))
+	line = strings.TrimSuffix(line, "\n")
+	rx := regexp.MustCompile(pattern)
+	return rx.FindStringSubmatch(line)
+}
+
+// imports returns true if the current file imports the specified package path.
+func (f *file) imports(importPath string) bool {
+	all := astutil.Imports(f.fset, f.f)
+	for _, p := range all {
+		for _, i := range p {
+			uq, err := strconv.Unquote(i.Path.Value)
+			if err == nil && importPath == uq {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// srcLine returns the complete line at p, including the terminating newline.
+func srcLine(src []byte, p token.Position) string {
+	// Run to end of line in both directions if not at line start/end.
+	lo, hi := p.Offset, p.Offset+1
+	for lo > 0 && src[lo-1] != '\n' {
+		lo--
+	}
+	for hi < len(src) && src[hi-1] != '\n' {
+		hi++
+	}
+	return string(src[lo:hi])
+}
+
+// lintNilDeref examines values that are compared to nil.
+// It complains if such a value is dereferenced on a path where the
+// comparison found it to be nil, such as after an if statement whose
+// nil branch does not return.
+func (f *file) lintNilDeref() {
+	if !f.pkg.enabled("nil-deref") {
+		return
+	}
+	reported := make(map[ast.Node]bool)
+	w := &flowWalker{}
+	w.expr = func(e ast.Expr, st flowState) {
+		inspectFlowExpr(e, func(n ast.Node) bool {
+			var x ast.Expr
+			what := "dereference"
+			switch n := n.(type) {
+			case *ast.StarExpr:
+				x = n.X
+			case *ast.CallExpr:
+				x, what = n.Fun, "function call"
+			case *ast.SelectorExpr:
+				x, what = n.X, "field access"
+				if _, ok := f.pkg.typesInfo.Uses[n.Sel].(*types.Func); ok {
+					what = "method call"
+				}
+			default:
+				return true
+			}
+			key, ok := w.flowKey(x)
+			if !ok {
+				return true
+			}
+			fct := st[key]
+			if (fct.nilness != isNil && fct.nilness != maybeNil) || reported[fct.origin] {
+				return true
+			}
+			reported[fct.origin] = true
+			conf, state := 0.8, "may be nil"
+			if fct.nilness == isNil {
+				conf, state = 0.9, "is nil"
+			}
+			if !f.derefPanics(n, x) {
+				conf -= 0.3
+			}
+			// The fact comes from a comparison, or from assigning nil.
+			line := f.fset.Position(fct.origin.Pos()).Line
+			origin, note := "check", "%s compared to nil here"
+			if _, ok := fct.origin.(*ast.BinaryExpr); !ok {
+				origin, note = "assignment", "%s set to nil here"
+			}
+			p := f.errorf(n, conf, category("nil"), "possible nil %s: %s %s here after the %s on line %d", what, key, state, origin, line)
+			p.Related = append(p.Related, f.related(fct.origin, note, key))
+			return true
+		})
+	}
+	f.walkFlow(w)
+}
+
+// derefPanics reports whether n, which uses x, is certain to panic when x is nil.
+// It is false for method calls through a nil pointer,
+// which may be handled by the method, and when type information is missing.
+func (f *file) derefPanics(n ast.Node, x ast.Expr) bool {
+	sel, ok := n.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	typ := f.pkg.typeOf(x)
+	if typ == nil {
+		return false
+	}
+	if types.IsInterface(typ) {
+		return true
+	}
+	switch obj := f.pkg.typesInfo.Uses[sel.Sel].(type) {
+	case *types.Var:
+		// A field of a nil pointer.
+		return true
+	case *types.Func:
+		// A method with a value receiver needs the value.
+		recv := obj.Type().(*types.Signature).Recv()
+		if recv == nil {
+			return false
+		}
+		_, ptr := recv.Type().(*types.Pointer)
+		return !ptr
+	}
+	return false
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// defaultIgnoredErrors is the set of functions and methods whose error results
+// are conventionally not checked, because they cannot fail or because there is
+// nothing sensible to do when they do.
+var defaultIgnoredErrors = map[string]bool{
+	"fmt.Print":                      true,
+	"fmt.Printf":                     true,
+	"fmt.Println":                    true,
+	"(*bytes.Buffer).Write":          true,
+	"(*bytes.Buffer).WriteByte":      true,
+	"(*bytes.Buffer).WriteRune":      true,
+	"(*bytes.Buffer).WriteString":    true,
+	"(*strings.Builder).Write":       true,
+	"(*strings.Builder).WriteByte":   true,
+	"(*strings.Builder).WriteRune":   true,
+	"(*strings.Builder).WriteString": true,
+	"(hash.Hash).Write":              true,
+}
+
+// ignoresErrors reports whether the error result of call may be dropped.
+// A method is looked up both by the type declaring it and by the static
+// type of the receiver, so that "(hash.Hash).Write" matches h.Write(b)
+// although Write is declared by io.Writer, which hash.Hash embeds.
+func (f *file) ignoresErrors(call *ast.CallExpr) bool {
+	fn := f.callee(call)
+	if fn == nil {
+		return false
+	}
+	names := []string{fn.FullName()}
+	if sel, ok := astutil.Unparen(call.Fun).(*ast.SelectorExpr); ok && fn.Type().(*types.Signature).Recv() != nil {
+		if typ := f.pkg.typeOf(sel.X); typ != nil {
+			recv := types.TypeString(typ, func(p *types.Package) string { return p.Path() })
+			names = append(names, "("+recv+")."+fn.Name())
+		}
+	}
+	if fn.Pkg() != nil && fn.Pkg().Path() == "fmt" && strings.HasPrefix(fn.Name(), "Fprint") && len(call.Args) > 0 {
+		// Printing to standard output or error is like fmt.Print.
+		if isPkgDot(call.Args[0], "os", "Stdout") || isPkgDot(call.Args[0], "os", "Stderr") {
+			return true
+		}
+	}
+	for _, name := range names {
+		if defaultIgnoredErrors[name] {
+			return true
+		}
+		for _, s := range f.pkg.linter.IgnoredErrors {
+			if s == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lintIgnoredErrors examines calls to functions that return an error.
+// It complains if the error is dropped, either by discarding all the
+// results of the call or by assigning the error to the blank identifier.
+func (f *file) lintIgnoredErrors() {
+	if !f.pkg.enabled("ignored-errors") {
+		return
+	}
+	f.walk(func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok {
+				if i := f.errorResult(call); i >= 0 {
+					f.reportIgnoredError(call, "not checked")
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) != 1 {
+				// Each call on the right produces a single value;
+				// the error of a multi-valued call can't be assigned here.
+				for i, rhs := range n.Rhs {
+					call, ok := rhs.(*ast.CallExpr)
+					if ok && f.errorResult(call) == 0 && isIdent(n.Lhs[i], "_") {
+						f.reportIgnoredError(call, "assigned to _")
+					}
+				}
+				return true
+			}
+			call, ok := n.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if i := f.errorResult(call); i >= 0 && i < len(n.Lhs) && isIdent(n.Lhs[i], "_") {
+				f.reportIgnoredError(call, "assigned to _")
+			}
+		}
+		return true
+	})
+}
+
+// errorResult returns the index of the error among the results of call,
+// or -1 if it doesn't return an error or the error may be ignored.
+func (f *file) errorResult(call *ast.CallExpr) int {
+	if f.ignoresErrors(call) {
+		return -1
+	}
+	switch t := f.pkg.typeOf(call).(type) {
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if types.Identical(t.At(i).Type(), errorType) {
+				return i
+			}
+		}
+	case nil:
+	default:
+		if types.Identical(t, errorType) {
+			return 0
+		}
+	}
+	return -1
+}
+
+// callee returns the function or method called by call,
+// or nil if it is not statically known.
+func (f *file) callee(call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := f.pkg.typesInfo.Uses[id].(*types.Func)
+	return fn
+}
+
+func (f *file) reportIgnoredError(call *ast.CallExpr, how string) {
+	conf := 0.8
+	if fn := f.callee(call); fn != nil && isReadOrWrite(fn) {
+		// A reader or writer, such as an io.Writer, an *os.File or an
+		// unexported writer of this package, loses data when it fails.
+		conf = 0.9
+	}
+	f.errorf(call, conf, category("errors"), "error return value of %s is %s", f.render(call.Fun), how)
+}
+
+// isReadOrWrite reports whether fn is a Read or Write method
+// with the signature of io.Reader or io.Writer.
+func isReadOrWrite(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() == nil || (fn.Name() != "Read" && fn.Name() != "Write") {
+		return false
+	}
+	params, results := sig.Params(), sig.Results()
+	return params.Len() == 1 && types.Identical(params.At(0).Type(), types.NewSlice(types.Typ[types.Byte])) &&
+		results.Len() == 2 && types.Identical(results.At(0).Type(), types.Typ[types.Int]) &&
+		types.Identical(results.At(1).Type(), errorType)
+}
+
+// lintRedundantNilChecks examines comparisons of slices and maps to nil.
+// It complains if the comparison only guards operations that behave the
+// same for nil and empty values, such as len, range and append,
+// or if it is combined with a length check that already covers the nil case.
+func (f *file) lintRedundantNilChecks() {
+	if !f.pkg.enabled("redundant-nil-check") {
+		return
+	}
+	f.walk(func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				f.checkEarlyNilReturns(n.Body)
+			}
+		case *ast.FuncLit:
+			f.checkEarlyNilReturns(n.Body)
+		case *ast.IfStmt:
+			// if x != nil { ... }
+			if n.Init != nil || n.Else != nil {
+				return true
+			}
+			id, kind := f.nilCompared(n.Cond, token.NEQ)
+			if id != nil && f.onlyNilSafeUses(id, kind, n.Body) {
+				f.errorf(n.Cond, 0.8, category("redundant"), "nil check on %s is redundant: every use of it in this block is safe on a nil %s", id.Name, kind)
+			}
+		case *ast.BinaryExpr:
+			// x == nil || len(x) == 0
+			// x != nil && len(x) > 0
+			var eq token.Token
+			var lenOps []token.Token
+			switch n.Op {
+			case token.LOR:
+				eq, lenOps = token.EQL, []token.Token{token.EQL}
+			case token.LAND:
+				eq, lenOps = token.NEQ, []token.Token{token.NEQ, token.GTR}
+			default:
+				return true
+			}
+			for _, pair := range [][2]ast.Expr{{n.X, n.Y}, {n.Y, n.X}} {
+				id, kind := f.nilCompared(pair[0], eq)
+				if id != nil && f.isLenZeroCheck(pair[1], id, lenOps) {
+					f.errorf(pair[0], 0.9, category("redundant"), "nil check on %s is redundant: the length check already covers a nil %s", id.Name, kind)
+					break
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkEarlyNilReturns looks for early returns at the top level of a function
+// body of the form
+//
+//	if x == nil {
+//		return ...
+//	}
+//
+// where x is a slice or map that the rest of the function only uses in ways
+// that are safe when x is nil, and the rest of the function returns the same
+// results when x is empty.
+func (f *file) checkEarlyNilReturns(body *ast.BlockStmt) {
+	for i, s := range body.List {
+		ifStmt, ok := s.(*ast.IfStmt)
+		if !ok || ifStmt.Init != nil || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+			continue
+		}
+		ret, ok := ifStmt.Body.List[0].(*ast.ReturnStmt)
+		if !ok {
+			continue
+		}
+		id, kind := f.nilCompared(ifStmt.Cond, token.EQL)
+		if id == nil || !f.returnsSameWhenEmpty(ret, id, body.List[i+1:]) {
+			continue
+		}
+		rest := make([]ast.Node, 0, len(body.List)-i-1)
+		for _, s := range body.List[i+1:] {
+			rest = append(rest, s)
+		}
+		if f.onlyNilSafeUses(id, kind, rest...) {
+			f.errorf(ifStmt.Cond, 0.8, category("redundant"), "nil check on %s is redundant: every later use of it is safe on a nil %s", id.Name, kind)
+		}
+	}
+}
+
+// returnsSameWhenEmpty reports whether ret, which returns early when the
+// variable denoted by id is nil, has the same effect as running rest with
+// an empty value instead. That is the case when rest only ranges over the
+// variable, which does nothing when it is empty, and then either falls off
+// the end of the function like a bare ret, or returns the same results as
+// ret. A result of id or len(id) in rest matches nil or 0 in ret.
+func (f *file) returnsSameWhenEmpty(ret *ast.ReturnStmt, id *ast.Ident, rest []ast.Stmt) bool {
+	obj := f.pkg.typesInfo.Uses[id]
+	isObj := func(e ast.Expr) bool {
+		id, ok := astutil.Unparen(e).(*ast.Ident)
+		return ok && f.pkg.typesInfo.Uses[id] == obj
+	}
+	final := &ast.ReturnStmt{}
+	if n := len(rest); n > 0 {
+		if r, ok := rest[n-1].(*ast.ReturnStmt); ok {
+			final, rest = r, rest[:n-1]
+		}
+	}
+	for _, s := range rest {
+		if r, ok := s.(*ast.RangeStmt); !ok || !isObj(r.X) {
+			return false
+		}
+	}
+	if len(ret.Results) != len(final.Results) {
+		return false
+	}
+	for i, early := range ret.Results {
+		late := final.Results[i]
+		switch {
+		case f.render(early) == f.render(late):
+		case isIdent(early, "nil") && isObj(late):
+		case isZero(early) && isLenCall(late) && isObj(late.(*ast.CallExpr).Args[0]):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isZero reports whether expr is the integer literal 0.
+func isZero(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.INT && lit.Value == "0"
+}
+
+// nilCompared returns the slice or map variable that cond compares to nil
+// using op, and its kind ("slice" or "map").
+// It returns nil if cond is not such a comparison.
+func (f *file) nilCompared(cond ast.Expr, op token.Token) (*ast.Ident, string) {
+	be, ok := astutil.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || be.Op != op {
+		return nil, ""
+	}
+	x := be.X
+	if isIdent(x, "nil") {
+		x = be.Y
+	} else if !isIdent(be.Y, "nil") {
+		return nil, ""
+	}
+	id, ok := astutil.Unparen(x).(*ast.Ident)
+	if !ok {
+		return nil, ""
+	}
+	if _, ok := f.pkg.typesInfo.Uses[id].(*types.Var); !ok {
+		return nil, ""
+	}
+	switch f.pkg.typeOf(id).Underlying().(type) {
+	case *types.Slice:
+		return id, "slice"
+	case *types.Map:
+		return id, "map"
+	}
+	return nil, ""
+}
+
+// isLenZeroCheck reports whether e compares len(id) to zero using one of ops,
+// where id denotes the same variable as the identifier in len(id).
+// The comparison may be written either way round, as len(x) == 0 or 0 == len(x);
+// ops are given for the first form.
+func (f *file) isLenZeroCheck(e ast.Expr, id *ast.Ident, ops []token.Token) bool {
+	be, ok := astutil.Unparen(e).(*ast.BinaryExpr)
+	if !ok {
+		return false
+	}
+	op := be.Op
+	x, y := astutil.Unparen(be.X), astutil.Unparen(be.Y)
+	if isZero(x) {
+		x, y = y, x
+		switch op {
+		case token.GTR:
+			op = token.LSS
+		case token.LSS:
+			op = token.GTR
+		case token.GEQ:
+			op = token.LEQ
+		case token.LEQ:
+			op = token.GEQ
+		}
+	}
+	if !isZero(y) || !isLenCall(x) {
+		return false
+	}
+	arg, ok := astutil.Unparen(x.(*ast.CallExpr).Args[0]).(*ast.Ident)
+	if !ok || f.pkg.typesInfo.Uses[arg] != f.pkg.typesInfo.Uses[id] {
+		return false
+	}
+	for _, o := range ops {
+		if op == o {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyNilSafeUses reports whether the variable denoted by id is used at least
+// once within nodes, and only by operations that behave the same whether it is
+// nil or empty: len, cap, range, append and comparison with nil for slices,
+// and len, range, delete, reads and comparison with nil for maps.
+// Indexing and slicing a slice are not among them: they may panic where
+// a nil check would have returned early.
+func (f *file) onlyNilSafeUses(id *ast.Ident, kind string, nodes ...ast.Node) bool {
+	obj := f.pkg.typesInfo.Uses[id]
+	isObj := func(e ast.Expr) (*ast.Ident, bool) {
+		id, ok := astutil.Unparen(e).(*ast.Ident)
+		return id, ok && f.pkg.typesInfo.Uses[id] == obj
+	}
+	var uses []*ast.Ident
+	safe := make(map[*ast.Ident]bool)
+	unsafe := make(map[*ast.Ident]bool)
+	markWritten := func(e ast.Expr) {
+		// Writing to a nil map panics.
+		if ix, ok := e.(*ast.IndexExpr); ok && kind == "map" {
+			if id, ok := isObj(ix.X); ok {
+				unsafe[id] = true
+			}
+		}
+	}
+	for _, node := range nodes {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.Ident:
+				if f.pkg.typesInfo.Uses[n] == obj {
+					uses = append(uses, n)
+				}
+			case *ast.CallExpr:
+				if len(n.Args) == 0 {
+					break
+				}
+				id, ok := isObj(n.Args[0])
+				if !ok {
+					break
+				}
+				fun, ok := n.Fun.(*ast.Ident)
+				if !ok {
+					break
+				}
+				if _, ok := f.pkg.typesInfo.Uses[fun].(*types.Builtin); !ok {
+					break
+				}
+				switch fun.Name {
+				case "len", "append":
+					safe[id] = true
+				case "cap":
+					safe[id] = kind == "slice"
+				case "delete":
+					safe[id] = kind == "map"
+				}
+			case *ast.RangeStmt:
+				if id, ok := isObj(n.X); ok {
+					safe[id] = true
+				}
+			case *ast.IndexExpr:
+				if id, ok := isObj(n.X); ok {
+					safe[id] = kind == "map"
+				}
+			case *ast.BinaryExpr:
+				if !isIdent(n.X, "nil") && !isIdent(n.Y, "nil") {
+					break
+				}
+				if id, ok := isObj(n.X); ok {
+					safe[id] = true
+				}
+				if id, ok := isObj(n.Y); ok {
+					safe[id] = true
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range n.Lhs {
+					markWritten(lhs)
+					// x = append(x, ...)
+					id, ok := isObj(lhs)
+					if !ok || len(n.Lhs) != len(n.Rhs) {
+						continue
+					}
+					if call, ok := n.Rhs[i].(*ast.CallExpr); ok && isIdent(call.Fun, "append") && len(call.Args) > 0 {
+						if _, ok := isObj(call.Args[0]); ok {
+							safe[id] = true
+						}
+					}
+				}
+			case *ast.IncDecStmt:
+				markWritten(n.X)
+			}
+			return true
+		})
+	}
+	if len(uses) == 0 {
+		return false
+	}
+	for _, u := range uses {
+		if !safe[u] || unsafe[u] {
+			return false
+		}
+	}
+	return true
+}
+
+// lintDuplicateConditions examines the if statements in each block.
+// It complains if an if statement repeats the condition of an earlier one
+// in the same block, and nothing in between can have changed its value.
+func (f *file) lintDuplicateConditions() {
+	if !f.pkg.enabled("duplicate-condition") {
+		return
+	}
+	f.walk(func(n ast.Node) bool {
+		var list []ast.Stmt
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		default:
+			return true
+		}
+		for i, s := range list {
+			first, ok := s.(*ast.IfStmt)
+			if !ok || first.Init != nil || !isSideEffectFree(first.Cond) {
+				continue
+			}
+			cond := f.render(first.Cond)
+			names := make(map[string]bool)
+			indirect := false // whether a function call might change the condition
+			ast.Inspect(first.Cond, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.Ident:
+					names[n.Name] = true
+				case *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr:
+					indirect = true
+				}
+				return true
+			})
+			changes := func(n ast.Node) bool {
+				for _, id := range assignedIdents(n) {
+					if names[id.Name] {
+						return true
+					}
+				}
+				return indirect && containsCall(n)
+			}
+			if changes(first) || f.isTerminating(first.Body.List) {
+				// If the first if statement leaves the block,
+				// the second is dead code, which lintDeadBranches reports.
+				continue
+			}
+			for _, s := range list[i+1:] {
+				if next, ok := s.(*ast.IfStmt); ok && next.Init == nil && f.render(next.Cond) == cond {
+					line := f.fset.Position(first.Cond.Pos()).Line
+					p := f.errorf(next.Cond, 0.8, category("redundant"), "condition %s duplicates the condition of the if statement on line %d", cond, line)
+					p.Related = append(p.Related, f.related(first.Cond, "first occurrence of %s", cond))
+					break
+				}
+				if changes(s) {
+					break
+				}
+			}
+		}
+		return true
+	})
+}
+
+// isSideEffectFree reports whether evaluating e can have no side effects,
+// which is the case if it is made up only of identifiers, literals, selectors,
+// operators, index expressions and calls to len and cap.
+func isSideEffectFree(e ast.Expr) bool {
+	free := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil, *ast.Ident, *ast.BasicLit, *ast.SelectorExpr, *ast.ParenExpr,
+			*ast.BinaryExpr, *ast.StarExpr, *ast.IndexExpr:
+		case *ast.UnaryExpr:
+			free = n.Op != token.ARROW
+		case *ast.CallExpr:
+			free = (isIdent(n.Fun, "len") || isIdent(n.Fun, "cap")) && len(n.Args) == 1
+		default:
+			free = false
+		}
+		return free
+	})
+	return free
+}
+
+// lintSliceBounds examines slices indexed or sliced by constants.
+// It complains if the index is out of range for every length the slice
+// can have at that point, or if the slice was checked against nil or
+// a shorter length but not against the length the index needs.
+func (f *file) lintSliceBounds() {
+	if !f.pkg.enabled("slice-bounds") {
+		return
+	}
+	w := &flowWalker{}
+	w.expr = func(e ast.Expr, st flowState) {
+		inspectFlowExpr(e, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IndexExpr:
+				if !isSlice(f.pkg.typeOf(n.X)) {
+					return true
+				}
+				i, ok := f.constInt(n.Index)
+				if !ok {
+					return true
+				}
+				key, ok := w.flowKey(n.X)
+				if !ok {
+					return true
+				}
+				fc, ok := st[key]
+				if !ok {
+					return true
+				}
+				line := f.fset.Position(fc.origin.Pos()).Line
+				var p *Problem
+				switch {
+				case fc.hasMax && i >= fc.maxLen:
+					p = f.errorf(n, 0.9, category("bounds"), "index %d is out of range: %s has at most %d elements here (see line %d)", i, key, fc.maxLen, line)
+				case i >= fc.minLen:
+					p = f.errorf(n, 0.8, category("bounds"), "index %d of %s is not guarded by a length check; the check on line %d doesn't ensure len(%s) > %d", i, key, line, key, i)
+				default:
+					return true
+				}
+				p.Related = append(p.Related, f.related(fc.origin, "what is known about %s comes from here", key))
+			case *ast.SliceExpr:
+				if !isSlice(f.pkg.typeOf(n.X)) {
+					return true
+				}
+				key, ok := w.flowKey(n.X)
+				if !ok || st[key].nilness != isNil {
+					return true
+				}
+				// A nil slice has no capacity, so only 0 may be used.
+				for _, x := range []ast.Expr{n.Low, n.High, n.Max} {
+					if i, ok := f.constInt(x); ok && i > 0 {
+						origin := st[key].origin
+						line := f.fset.Position(origin.Pos()).Line
+						p := f.errorf(n, 0.9, category("bounds"), "slice bounds out of range: %s is nil here (see line %d)", key, line)
+						p.Related = append(p.Related, f.related(origin, "%s is known to be nil from here", key))
+						break
+					}
+				}
+			}
+			return true
+		})
+	}
+	f.walkFlow(w)
+}
+
+// lintDeadBranches examines the conditions of if statements.
+// It complains if an earlier check of the same variables decides the
+// condition, so that one of the branches can never be taken.
+func (f *file) lintDeadBranches() {
+	if !f.pkg.enabled("dead-branch") {
+		return
+	}
+	w := &flowWalker{}
+	w.branch = func(s *ast.IfStmt, st flowState) {
+		truth, origin, ok := w.decide(st, s.Cond)
+		if !ok {
+			return
+		}
+		cond := f.render(s.Cond)
+		line := f.fset.Position(origin.Pos()).Line
+		var p *Problem
+		switch {
+		case !truth:
+			p = f.errorf(s, 0.9, category("dead-code"), "condition %s is always false here because of the check on line %d, so this branch is never taken", cond, line)
+		case s.Else != nil:
+			p = f.errorf(s.Else, 0.9, category("dead-code"), "condition %s is always true here because of the check on line %d, so the else branch is never taken", cond, line)
+		default:
+			p = f.errorf(s.Cond, 0.8, category("dead-code"), "condition %s is always true here because of the check on line %d", cond, line)
+		}
+		p.Related = append(p.Related, f.related(origin, "the earlier check that decides %s", cond))
+	}
+	f.walkFlow(w)
+}
+
+// sortedFiles returns the files of the package sorted by name,
+// so that package-wide rules report problems in a stable order.
+func (p *pkg) sortedFiles() []*file {
+	files := make([]*file, 0, len(p.files))
+	for _, f := range p.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].filename < files[j].filename })
+	return files
+}
+
+// lintPackageComments examines the package comments of all the files in the package together.
+// It complains if there is no package comment at all, if one is not of the
+// standard form, if several files have one, or if a file other than doc.go has
+// one when the package has a doc.go file.
+// Each of these is reported once for the package.
+func (p *pkg) lintPackageComments() {
+	if !p.enabled("package-comments") {
+		return
+	}
+	var files []*file
+	for _, f := range p.sortedFiles() {
+		if !f.isTest() {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	const ref = styleGuideBase + "#package-comments"
+	name := files[0].f.Name.Name
+	var docGo *file
+	var commented []*file
+	for _, f := range files {
+		if filepath.Base(f.filename) == "doc.go" {
+			docGo = f
+		}
+		if f.f.Doc != nil {
+			commented = append(commented, f)
+		}
+	}
+	if len(commented) == 0 {
+		f := files[0]
+		if docGo != nil {
+			f = docGo
+		}
+		f.errorf(f.f.Name, 0.9, link(ref), category("comments"), "package %s should have a package comment", name)
+		return
+	}
+
+	if name != "main" {
+		prefix := "Package " + name + " "
+		for _, f := range commented {
+			// A leading space is reported by lintPackageComment.
+			if !strings.HasPrefix(strings.TrimLeft(f.f.Doc.Text(), " \t"), prefix) {
+				f.errorf(f.f.Doc, 1, link(ref), category("comments"), `package comment should be of the form "%s..."`, prefix)
+			}
+		}
+	}
+
+	if docGo != nil {
+		var stray []*file
+		for _, f := range commented {
+			if f != docGo {
+				stray = append(stray, f)
+			}
+		}
+		if len(stray) > 0 {
+			pr := stray[0].errorf(stray[0].f.Doc, 0.9, link(ref), category("comments"), "package comment for %s should be in doc.go", name)
+			for _, f := range stray[1:] {
+				pr.Related = append(pr.Related, f.related(f.f.Doc, "another package comment outside doc.go"))
+			}
+			if docGo.f.Doc != nil {
+				pr.Related = append(pr.Related, docGo.related(docGo.f.Doc, "the package comment in doc.go"))
+			}
+		}
+		return
+	}
+
+	if len(commented) > 1 {
+		first := commented[0]
+		pr := commented[1].errorf(commented[1].f.Doc, 0.9, link(ref), category("comments"), "package %s has a package comment in %d files; it should have only one", name, len(commented))
+		pr.Related = append(pr.Related, first.related(first.f.Doc, "first package comment"))
+		for _, f := range commented[2:] {
+			pr.Related = append(pr.Related, f.related(f.f.Doc, "another package comment"))
+		}
+	}
+}
+
+// lintErrorPrefixes examines package-level variables holding exported sentinel errors.
+// It complains if their message isn't prefixed by the package name;
+// their names are checked by lintErrors.
+func (f *file) lintErrorPrefixes() {
+	if !f.pkg.enabled("error-message-prefix") || f.pkg.main {
+		return
+	}
+	for _, decl := range f.f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || len(vs.Values) != 1 {
+				continue
+			}
+			call, ok := vs.Values[0].(*ast.CallExpr)
+			if !ok || !f.isErrorConstructor(call) {
+				continue
+			}
+			id := vs.Names[0]
+			if !id.IsExported() || len(call.Args) == 0 {
+				continue
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			msg, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				continue
+			}
+			pkg := f.f.Name.Name
+			if !strings.HasPrefix(msg, pkg+": ") && !strings.HasPrefix(msg, pkg+".") {
+				f.errorf(lit, 0.8, category("errors"), "message of exported error %s should be prefixed by the package name, as in %q", id.Name, pkg+": "+msg)
+			}
+		}
+	}
+}
+
+// lintErrorConstructors examines the messages passed to error constructors.
+// It makes the same complaints as lintErrorStrings, which it replaces, but
+// resolves the callee with type information, so that it sees through renamed
+// imports and knows of the constructors configured in the Linter.
+// It also complains about calls to fmt.Errorf that wrap more than one error with %w.
+func (f *file) lintErrorConstructors() {
+	if !f.pkg.enabled("error-strings") {
+		return
+	}
+	f.walk(func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		if !f.isErrorConstructor(call) {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		s, _ := strconv.Unquote(lit.Value) // can assume well-formed Go
+		if s == "" {
+			return true
+		}
+		isErrorf := isPkgDot(call.Fun, "fmt", "Errorf")
+		if fn := f.callee(call); fn != nil {
+			isErrorf = fn.FullName() == "fmt.Errorf"
+		}
+		if isErrorf {
+			if n := countWrapVerbs(s); n > 1 {
+				f.errorf(lit, 0.8, category("errors"), "fmt.Errorf call has %d %%w verbs; an error should wrap at most one other", n)
+			}
+		}
+		if clean, conf := lintErrorString(s); !clean {
+			f.errorf(lit, conf, link(styleGuideBase+"#error-strings"), category("errors"),
+				"error strings should not be capitalized or end with punctuation or a newline")
+		}
+		return true
+	})
+}
+
+// countWrapVerbs returns the number of %w verbs in the format string s.
+func countWrapVerbs(s string) int {
+	n := 0
+	verbs, _ := formatVerbs(s)
+	for _, v := range verbs {
+		if v.verb == 'w' {
+			n++
+		}
+	}
+	return n
+}
+
+// A formatVerb is a verb of a Printf-style format string.
+type formatVerb struct {
+	verb  byte
+	plain bool // no flags, width or precision, as in "%v"
+	off   int  // offset of the verb character in the format string
+}
+
+// formatVerbs returns the verbs of the format string s, except for %%.
+// positional is false if s uses argument indexes or * for width or precision,
+// in which case the verbs don't correspond to the arguments in order.
+func formatVerbs(s string) (verbs []formatVerb, positional bool) {
+	positional = true
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		start := i
+		// Skip flags, width, precision and argument indexes to find the verb.
+		for i++; i < len(s) && strings.IndexByte("+-# 0123456789.[]*", s[i]) >= 0; i++ {
+			if s[i] == '[' || s[i] == '*' {
+				positional = false
+			}
+		}
+		if i == len(s) {
+			break
+		}
+		if s[i] != '%' {
+			verbs = append(verbs, formatVerb{verb: s[i], plain: i == start+1, off: i})
+		}
+	}
+	return verbs, positional
+}
+
+// lintErrorWrapping examines errors made from other errors.
+// It complains about fmt.Errorf calls that format an error with %v or %s,
+// where %w would let callers unwrap the cause, and about errors.New(err.Error()),
+// which drops the cause altogether.
+func (f *file) lintErrorWrapping() {
+	if !f.pkg.enabled("error-wrapping") {
+		return
+	}
+	f.walk(func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		fn := f.callee(call)
+		if fn == nil {
+			return true
+		}
+		switch fn.FullName() {
+		case "errors.New":
+			if arg, ok := astutil.Unparen(call.Args[0]).(*ast.CallExpr); ok && len(arg.Args) == 0 {
+				if sel, ok := arg.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Error" && f.isError(f.pkg.typeOf(sel.X)) {
+					f.errorf(call, 0.8, category("errors"), "errors.New(%s) drops the cause; wrap it with fmt.Errorf and %%w, or return it as is", f.render(arg))
+				}
+			}
+		case "fmt.Errorf":
+			f.checkWrapVerbs(call)
+		}
+		return true
+	})
+}
+
+// checkWrapVerbs reports error operands of the fmt.Errorf call
+// formatted with %v or %s, suggesting %w instead.
+func (f *file) checkWrapVerbs(call *ast.CallExpr) {
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	// Work on the literal as written so that offsets map to the source.
+	verbs, positional := formatVerbs(lit.Value[1 : len(lit.Value)-1])
+	if !positional || len(verbs) != len(call.Args)-1 {
+		return
+	}
+	var cands []formatVerb
+	for i, v := range verbs {
+		switch {
+		case v.verb == 'w':
+			// The call already wraps an error; it may wrap only one.
+			return
+		case v.plain && (v.verb == 'v' || v.verb == 's') && f.isError(f.pkg.typeOf(call.Args[i+1])):
+			cands = append(cands, v)
+		}
+	}
+	if len(cands) == 0 {
+		return
+	}
+	// The operands are known to be errors, so the suggestion is rarely wrong.
+	p := f.errorf(call.Args[0], 0.8, category("errors"), "error operand is formatted with %%%c; use %%w to let callers unwrap it", cands[0].verb)
+	if len(cands) == 1 {
+		pos := lit.Pos() + 1 + token.Pos(cands[0].off)
+		p.Edits = []Edit{f.edit(pos, pos+1, "w")}
+	}
+}
+
+// isError reports whether values of type t implement error.
+func (f *file) isError(t types.Type) bool {
+	return t != nil && types.Implements(t, errorType.Underlying().(*types.Interface))
+}
+
+// lintUnexportedParams examines the parameters of exported functions and methods.
+// It complains if their types are unexported, since callers in other packages
+// can then only pass nil or values obtained from this package.
+// Results are checked by lintUnexportedReturn.
+func (f *file) lintUnexportedParams() {
+	if !f.pkg.enabled("unexported-param") {
+		return
+	}
+	for _, decl := range f.f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Type.Params == nil {
+			continue
+		}
+		thing := "func"
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			thing = "method"
+			if !ast.IsExported(receiverType(fn)) {
+				// As in lintUnexportedReturn, methods of unexported types
+				// are usually implementations of interfaces.
+				continue
+			}
+		}
+		for _, param := range fn.Type.Params.List {
+			expr := param.Type
+			if ell, ok := expr.(*ast.Ellipsis); ok {
+				expr = ell.Elt
+			}
+			typ := f.pkg.typeOf(expr)
+			if typ == nil || exportedType(typ) {
+				continue
+			}
+			f.errorf(param.Type, 0.8, link(styleGuideBase), category("unexported-type-in-api"),
+				"exported %s %s takes unexported type %s, which can be annoying to use",
+				thing, fn.Name.Name, typ)
+			break // only flag one
+		}
+	}
+}
+
+// lintReceivers examines the receivers of the methods of each type in the package.
+// It complains if a type's methods name their receiver differently in different
+// files, if a receiver name is long, or if a type mixes pointer and value receivers.
+// Inconsistent names within one file and names such as "this" are reported by lintReceiverNames.
+func (p *pkg) lintReceivers() {
+	type method struct {
+		f    *file
+		fn   *ast.FuncDecl
+		name string
+	}
+	const ref = styleGuideBase + "#receiver-names"
+	firstNamed := make(map[string]method)
+	firstOfKind := make(map[string]method)
+	mixed := make(map[string]bool)
+	for _, f := range p.sortedFiles() {
+		for _, decl := range f.f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			field := fn.Recv.List[0]
+			recv := receiverType(fn)
+			m := method{f: f, fn: fn}
+			if len(field.Names) > 0 {
+				m.name = field.Names[0].Name
+			}
+
+			// lintReceiverNames reports underscores and names such as "this".
+			generic := m.name == "_" || m.name == "this" || m.name == "self"
+			if m.name != "" && !generic && p.enabled("receiver-names") {
+				if prev, ok := firstNamed[recv]; !ok {
+					firstNamed[recv] = m
+				} else if prev.name != m.name && prev.f != f {
+					problem := f.errorf(field, 0.9, link(ref), category("naming"), "receiver name %s should be consistent with receiver name %s used for %s in %s", m.name, prev.name, recv, filepath.Base(prev.f.filename))
+					problem.Related = append(problem.Related, prev.f.related(prev.fn.Recv.List[0], "receiver named %s here", prev.name))
+				}
+				if utf8.RuneCountInString(m.name) > 3 {
+					f.errorf(field.Names[0], 0.8, link(ref), category("naming"), "receiver name %s should be short, such as a one or two letter abbreviation of %s", m.name, recv)
+				}
+			}
+
+			if !p.enabled("receiver-kinds") {
+				continue
+			}
+			_, ptr := astutil.Unparen(field.Type).(*ast.StarExpr)
+			kind := "value"
+			if ptr {
+				kind = "pointer"
+			}
+			prev, ok := firstOfKind[recv]
+			if !ok {
+				firstOfKind[recv] = m
+				continue
+			}
+			if _, prevPtr := astutil.Unparen(prev.fn.Recv.List[0].Type).(*ast.StarExpr); prevPtr != ptr && !mixed[recv] {
+				// Report only the first method that breaks with the type's earlier ones.
+				mixed[recv] = true
+				problem := f.errorf(field, 0.8, category("naming"), "method %s.%s has a %s receiver, unlike %s.%s; a type's methods should all have pointer receivers or all have value receivers", recv, fn.Name.Name, kind, recv, prev.fn.Name.Name)
+				problem.Related = append(problem.Related, prev.f.related(prev.fn.Recv.List[0], "%s.%s declared here", recv, prev.fn.Name.Name))
+			}
+		}
+	}
+}
+
+// limit returns n, or def if n is not positive.
+func limit(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+// lintSignatures examines the signatures of functions and methods.
+// It complains if they have too many parameters or results, or long runs
+// of adjacent parameters that share a bool or integer type, such as
+// f(a, b, c, d int), which callers can easily pass in the wrong order.
+func (f *file) lintSignatures() {
+	if !f.pkg.enabled("signature-complexity") {
+		return
+	}
+	l := f.pkg.linter
+	maxParams := limit(l.MaxParams, 5)
+	maxResults := limit(l.MaxResults, 2)
+	maxSameTyped := limit(l.MaxSameTypedParams, 3)
+	for _, decl := range f.f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		thing, name := "func", fn.Name.Name
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			thing, name = "method", receiverType(fn)+"."+name
+		}
+		params := fn.Type.Params.NumFields()
+		if params > maxParams {
+			f.errorf(fn.Type.Params, 0.7, category("complexity"), "%s %s has %d parameters, more than %d; consider grouping them in an options struct", thing, name, params, maxParams)
+		} else if run, typ := f.longestSameTypedRun(fn.Type.Params); run > maxSameTyped {
+			f.errorf(fn.Type.Params, 0.8, category("complexity"), "%s %s has %d adjacent parameters of type %s, which callers can easily mix up; consider an options struct or distinct types", thing, name, run, typ)
+		}
+		if results := fn.Type.Results.NumFields(); results > maxResults {
+			suggest := "consider returning a struct"
+			if len(fn.Type.Results.List[0].Names) == 0 {
+				suggest = "consider naming the results or returning a struct"
+			}
+			f.errorf(fn.Type.Results, 0.8, category("complexity"), "%s %s has %d results, more than %d; %s", thing, name, results, maxResults, suggest)
+		}
+	}
+}
+
+// longestSameTypedRun returns the length of the longest run of adjacent
+// parameters in params that share a bool or integer type, and that type.
+func (f *file) longestSameTypedRun(params *ast.FieldList) (int, types.Type) {
+	var (
+		best, run     int
+		bestTyp, prev types.Type
+	)
+	for _, field := range params.List {
+		typ := f.pkg.typeOf(field.Type)
+		basic, ok := typ.(*types.Basic)
+		if !ok || basic.Info()&(types.IsBoolean|types.IsInteger) == 0 {
+			run, prev = 0, nil
+			continue
+		}
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		if prev != nil && types.Identical(prev, typ) {
+			run += n
+		} else {
+			run = n
+		}
+		prev = typ
+		if run > best {
+			best, bestTyp = run, typ
+		}
+	}
+	return best, bestTyp
+}
+
+// lintGetters examines exported methods named like GetFoo that take no
+// arguments, or only a context, and return a value. It complains that they
+// should be named Foo, as Effective Go advises, and offers a fix renaming
+// the method and its uses in the package.
+// Methods that an interface in scope requires, and protobuf messages, are exempt;
+// generated files are not linted at all. HTTP handlers such as GetUser(w, r)
+// take other arguments and return nothing, so they are not getters.
+func (f *file) lintGetters() {
+	if !f.pkg.enabled("getters") || f.pkg.typesPkg == nil {
+		return
+	}
+	for _, decl := range f.f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || !fn.Name.IsExported() || fn.Type.Results == nil {
+			continue
+		}
+		name := fn.Name.Name
+		if !strings.HasPrefix(name, "Get") {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(name[len("Get"):]); !unicode.IsUpper(r) {
+			continue
+		}
+		if n := fn.Type.Params.NumFields(); n > 1 || n == 1 && !isContext(f.pkg.typeOf(fn.Type.Params.List[0].Type)) {
+			continue
+		}
+		obj, ok := f.pkg.typesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		recv := obj.Type().(*types.Signature).Recv().Type()
+		if f.pkg.requiredByInterface(recv, name) || hasMethod(recv, "ProtoMessage") {
+			continue
+		}
+		newName := name[len("Get"):]
+		p := f.errorf(fn.Name, 0.8, link("https://golang.org/doc/effective_go.html#Getters"), category("naming"),
+			"getter %s.%s should be named %s, without the Get prefix", receiverType(fn), name, newName)
+		if o, _, _ := types.LookupFieldOrMethod(recv, true, obj.Pkg(), newName); o != nil {
+			// Renaming would clash with an existing field or method.
+			continue
+		}
+		p.Edits = f.pkg.renameEdits(obj, newName)
+		if doc := fn.Doc; doc != nil && strings.HasPrefix(doc.List[0].Text, "// "+name+" ") {
+			// Keep the doc comment in the form "Item ...".
+			pos := doc.List[0].Pos() + token.Pos(len("// "))
+			p.Edits = append(p.Edits, f.edit(pos, pos+token.Pos(len(name)), newName))
+		}
+	}
+}
+
+// isContext reports whether typ is context.Context.
+func isContext(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// hasMethod reports whether typ, or a pointer to it, has a method with the given name.
+func hasMethod(typ types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// requiredByInterface reports whether an interface type declared in the package
+// or in one of its imports has a method with the given name that recv implements.
+func (p *pkg) requiredByInterface(recv types.Type, name string) bool {
+	scopes := []*types.Scope{p.typesPkg.Scope()}
+	for _, imp := range p.typesPkg.Imports() {
+		scopes = append(scopes, imp.Scope())
+	}
+	for _, scope := range scopes {
+		for _, n := range scope.Names() {
+			tn, ok := scope.Lookup(n).(*types.TypeName)
+			if !ok {
+				continue
+			}
+			iface, ok := tn.Type().Underlying().(*types.Interface)
+			if !ok {
+				continue
+			}
+			for i := 0; i < iface.NumMethods(); i++ {
+				if iface.Method(i).Name() == name && types.Implements(recv, iface) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// renameEdits returns the edits renaming obj to newName
+// at its declaration and at each of its uses in the package.
+func (p *pkg) renameEdits(obj types.Object, newName string) []Edit {
+	var edits []Edit
+	for _, f := range p.sortedFiles() {
+		ast.Inspect(f.f, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && (p.typesInfo.Defs[id] == obj || p.typesInfo.Uses[id] == obj) {
+				edits = append(edits, f.edit(id.Pos(), id.End(), newName))
+			}
+			return true
+		})
+	}
+	return edits
+}
+
+// lintNakedReturns examines naked returns in functions with named results.
+// It complains about them in functions longer than a few lines, where the
+// declaration of the results is far away, and about those that return a
+// named result before anything was assigned to it.
+func (f *file) lintNakedReturns() {
+	if !f.pkg.enabled("naked-returns") {
+		return
+	}
+	maxLines := limit(f.pkg.linter.MaxNakedReturnLines, 10)
+	f.walk(func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			f.checkNakedReturns("func "+fn.Name.Name, fn.Type, fn.Body, maxLines)
+		case *ast.FuncLit:
+			f.checkNakedReturns("func literal", fn.Type, fn.Body, maxLines)
+		}
+		return true
+	})
+}
+
+// checkNakedReturns reports the naked returns of the function called name
+// with the given type and body, not counting those of nested function literals.
+func (f *file) checkNakedReturns(name string, typ *ast.FuncType, body *ast.BlockStmt, maxLines int) {
+	if body == nil || typ.Results == nil || len(typ.Results.List[0].Names) == 0 {
+		return
+	}
+	var names []string
+	var objs []types.Object
+	canFix := true
+	for _, field := range typ.Results.List {
+		for _, id := range field.Names {
+			names = append(names, id.Name)
+			if obj := f.pkg.typesInfo.Defs[id]; obj != nil {
+				objs = append(objs, obj)
+			}
+			if id.Name == "_" {
+				canFix = false
+			}
+		}
+	}
+
+	// Find where each result is first assigned, including in closures
+	// such as deferred functions. Taking its address counts as assigning it.
+	assigned := make(map[types.Object]token.Pos)
+	record := func(e ast.Expr) {
+		id, ok := astutil.Unparen(e).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if obj := f.pkg.typesInfo.Uses[id]; obj != nil {
+			if pos, ok := assigned[obj]; !ok || id.Pos() < pos {
+				assigned[obj] = id.Pos()
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				record(lhs)
+			}
+		case *ast.IncDecStmt:
+			record(n.X)
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				record(n.Key)
+				if n.Value != nil {
+					record(n.Value)
+				}
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				record(n.X)
+			}
+		}
+		return true
+	})
+
+	lines := f.fset.Position(body.End()).Line - f.fset.Position(typ.Pos()).Line + 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) > 0 {
+				return true
+			}
+			if lines > maxLines {
+				p := f.errorf(n, 0.8, link(styleGuideBase+"#named-result-parameters"), category("style"),
+					"naked return in %s, which is %d lines long; return the results explicitly", name, lines)
+				if canFix {
+					p.Edits = []Edit{f.edit(n.Pos(), n.End(), "return "+strings.Join(names, ", "))}
+				}
+			}
+			var unassigned []string
+			for _, obj := range objs {
+				if pos, ok := assigned[obj]; (!ok || pos > n.Pos()) && obj.Name() != "_" {
+					unassigned = append(unassigned, obj.Name())
+				}
+			}
+			if len(unassigned) > 0 {
+				f.errorf(n, 0.6, category("style"), "naked return in %s returns %s before any assignment; return the zero values explicitly",
+					name, strings.Join(unassigned, ", "))
+			}
+		}
+		return true
+	})
+}
+
+// lintShadowing examines local variable declarations in inner blocks.
+// It complains if they shadow a parameter, a named result or a variable
+// named err of an enclosing scope that is used after the inner block,
+// where the code likely meant to use or assign the outer variable.
+// Rebinding a variable to the result of a type assertion on it, as in
+// if w, ok := w.(io.ReaderFrom); ok, is idiomatic and reported with low confidence.
+func (f *file) lintShadowing() {
+	if !f.pkg.enabled("shadowing") || f.pkg.typesPkg == nil {
+		return
+	}
+	info := f.pkg.typesInfo
+
+	// Record the uses of each object in the file, and the naked returns
+	// of each function, which implicitly use its named results.
+	uses := make(map[types.Object][]token.Pos)
+	params := make(map[types.Object]*types.Scope)
+	results := make(map[types.Object]bool)
+	nakedReturns := make(map[*types.Scope][]token.Pos)
+	ast.Inspect(f.f, func(n ast.Node) bool {
+		var typ *ast.FuncType
+		var body *ast.BlockStmt
+		switch n := n.(type) {
+		case *ast.Ident:
+			if obj := info.Uses[n]; obj != nil {
+				uses[obj] = append(uses[obj], n.Pos())
+			}
+			return true
+		case *ast.FuncDecl:
+			typ, body = n.Type, n.Body
+		case *ast.FuncLit:
+			typ, body = n.Type, n.Body
+		default:
+			return true
+		}
+		scope := info.Scopes[typ]
+		if scope == nil || body == nil {
+			return true
+		}
+		for _, list := range []*ast.FieldList{typ.Params, typ.Results} {
+			if list == nil {
+				continue
+			}
+			for _, field := range list.List {
+				for _, id := range field.Names {
+					if obj := info.Defs[id]; obj != nil {
+						params[obj] = scope
+						results[obj] = list == typ.Results
+					}
+				}
+			}
+		}
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				if len(n.Results) == 0 {
+					nakedReturns[scope] = append(nakedReturns[scope], n.Pos())
+				}
+			}
+			return true
+		})
+		return true
+	})
+
+	// usedAfter reports whether obj is used after the position end.
+	usedAfter := func(obj types.Object, end token.Pos) bool {
+		for _, pos := range uses[obj] {
+			if pos >= end {
+				return true
+			}
+		}
+		if scope, ok := params[obj]; ok {
+			for _, pos := range nakedReturns[scope] {
+				if pos >= end {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	f.walk(func(n ast.Node) bool {
+		var lhs, rhs []ast.Expr
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			lhs, rhs = n.Lhs, n.Rhs
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				lhs = append(lhs, id)
+			}
+			rhs = n.Values
+		default:
+			return true
+		}
+		for _, e := range lhs {
+			id, ok := e.(*ast.Ident)
+			if !ok || id.Name == "_" {
+				continue
+			}
+			obj, ok := info.Defs[id].(*types.Var)
+			if !ok || obj.Parent() == nil || obj.Parent().Parent() == nil {
+				continue
+			}
+			inner := obj.Parent()
+			_, outer := inner.Parent().LookupParent(id.Name, id.Pos())
+			outerVar, ok := outer.(*types.Var)
+			if !ok || outerVar.Parent() == f.pkg.typesPkg.Scope() {
+				continue
+			}
+			var what string
+			switch {
+			case results[outerVar]:
+				what = "named result"
+			case params[outerVar] != nil:
+				what = "parameter"
+			case id.Name == "err":
+				what = "variable"
+			default:
+				continue
+			}
+			if !usedAfter(outerVar, inner.End()) {
+				continue
+			}
+			conf := 0.8
+			if rebinds(info, rhs, outerVar) {
+				conf = 0.4
+			}
+			p := f.errorf(id, conf, category("shadowing"), "declaration of %s shadows the %s %s of an enclosing scope, which is used after this block", id.Name, what, id.Name)
+			p.Related = append(p.Related, f.related(outerVar, "shadowed %s declared here", id.Name))
+		}
+		return true
+	})
+}
+
+// rebinds reports whether rhs is a single type assertion on obj, or obj itself,
+// as in the idioms w, ok := w.(io.ReaderFrom) and v := v.
+func rebinds(info *types.Info, rhs []ast.Expr, obj types.Object) bool {
+	if len(rhs) != 1 {
+		return false
+	}
+	e := astutil.Unparen(rhs[0])
+	if ta, ok := e.(*ast.TypeAssertExpr); ok {
+		e = astutil.Unparen(ta.X)
+	}
+	id, ok := e.(*ast.Ident)
+	return ok && info.Uses[id] == obj
+}
+
+// builtinSince maps predeclared identifiers added after Go 1.0
+// to the version of Go that added them.
+var builtinSince = map[string]string{
+	"any":        "1.18",
+	"comparable": "1.18",
+	"clear":      "1.21",
+	"max":        "1.21",
+	"min":        "1.21",
+}
+
+// A module describes the module containing a package, as declared by its go.mod file.
+type module struct {
+	dir       string // directory containing the go.mod file
+	path      string // module path
+	goVersion string // Go version, such as "1.21"
+}
+
+// dir returns the absolute path of the directory containing
+// the package's files, or "" if it is not known.
+func (p *pkg) dir() string {
+	for _, f := range p.files {
+		abs, err := filepath.Abs(f.filename)
+		if err != nil {
+			return ""
+		}
+		return filepath.Dir(abs)
+	}
+	return ""
+}
+
+// module returns the module containing the package,
+// or nil if there is no go.mod file above its directory.
+// Following the go command, a go.mod file without a go directive means Go 1.16.
+// The go.mod file is only read on the first call.
+func (p *pkg) module() *module {
+	if p.moduleRead {
+		return p.mod
+	}
+	p.moduleRead = true
+	dir := p.dir()
+	for dir != "" {
+		if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			m := &module{dir: dir, goVersion: "1.16"}
+			for _, line := range strings.Split(string(data), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) != 2 {
+					continue
+				}
+				switch fields[0] {
+				case "module":
+					m.path = fields[1]
+					if path, err := strconv.Unquote(fields[1]); err == nil {
+						m.path = path
+					}
+				case "go":
+					m.goVersion = fields[1]
+				}
+			}
+			p.mod = m
+			return m
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return nil
+}
+
+// goVersion returns the Go version declared by the go.mod file of
+// the module containing the package, such as "1.21". It returns ""
+// if there is no go.mod file, in which case the predeclared
+// identifiers of the running Go are assumed.
+func (p *pkg) goVersion() string {
+	if m := p.module(); m != nil {
+		return m.goVersion
+	}
+	return ""
+}
+
+// versionAtLeast reports whether the Go version v, such as "1.21.3",
+// is at least want, such as "1.21".
+func versionAtLeast(v, want string) bool {
+	vs, ms := strings.Split(v, "."), strings.Split(want, ".")
+	for i := 0; i < len(ms); i++ {
+		if i >= len(vs) {
+			return false
+		}
+		a, _ := strconv.Atoi(vs[i])
+		b, _ := strconv.Atoi(ms[i])
+		if a != b {
+			return a > b
+		}
+	}
+	return true
+}
+
+// lintShadowedNames examines the declarations of the package.
+// It complains about those that shadow a predeclared identifier existing
+// in the Go version of the module, such as len or error, or a package
+// imported by the file, and about package-level declarations named
+// after the package itself, such as type fmt in package fmt.
+func (p *pkg) lintShadowedNames() {
+	if !p.enabled("shadowed-names") || p.typesInfo == nil {
+		return
+	}
+	version := p.goVersion()
+	for _, f := range p.sortedFiles() {
+		f.lintShadowedNames(version)
+	}
+}
+
+// lintShadowedNames examines the declarations of the file for lintShadowedNames
+// of the package. version is the Go version of the module, or "" if unknown.
+func (f *file) lintShadowedNames(version string) {
+	imported := make(map[string]*ast.ImportSpec)
+	for _, is := range f.f.Imports {
+		if name := f.importName(is); name != "_" && name != "." {
+			imported[name] = is
+		}
+	}
+	pkgScope := f.pkg.typesPkg.Scope()
+	ast.Inspect(f.f, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id.Name == "_" {
+			return true
+		}
+		obj := f.pkg.typesInfo.Defs[id]
+		if obj == nil || obj.Parent() == nil {
+			// Fields and methods have no scope to shadow in.
+			return true
+		}
+		if _, ok := obj.(*types.PkgName); ok {
+			return true
+		}
+		if is, ok := imported[id.Name]; ok && obj.Parent() != pkgScope {
+			p := f.errorf(id, 0.8, category("shadowing"), "declaration of %s shadows the imported package %s", id.Name, id.Name)
+			p.Related = append(p.Related, f.related(is, "package %s imported here", id.Name))
+			return true
+		}
+		if u := types.Universe.Lookup(id.Name); u != nil {
+			if since, ok := builtinSince[id.Name]; ok && version != "" && !versionAtLeast(version, since) {
+				return true
+			}
+			kind := "builtin function"
+			switch u.(type) {
+			case *types.TypeName:
+				kind = "predeclared type"
+			case *types.Const, *types.Nil:
+				kind = "predeclared constant"
+			}
+			f.errorf(id, 0.8, category("shadowing"), "declaration of %s shadows the %s %s", id.Name, kind, id.Name)
+			return true
+		}
+		if obj.Parent() == pkgScope && id.Name == f.f.Name.Name && !f.pkg.main {
+			p := f.errorf(id, 0.8, category("shadowing"), "declaration of %s has the same name as its package", id.Name)
+			p.Related = append(p.Related, f.related(f.f.Name, "package %s declared here", id.Name))
+		}
+		return true
+	})
+}
+
+// importName returns the name under which the import spec is used in the file.
+func (f *file) importName(is *ast.ImportSpec) string {
+	if is.Name != nil {
+		return is.Name.Name
+	}
+	path, _ := strconv.Unquote(is.Path.Value)
+	if f.pkg.typesPkg != nil {
+		for _, imp := range f.pkg.typesPkg.Imports() {
+			if imp.Path() == path {
+				return imp.Name()
+			}
+		}
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// lintSilentNilInput examines functions that start by checking whether
+// a parameter is nil. It complains if they then return a nil error,
+// or nil from a constructor, since callers passing nil by mistake
+// get no sign of it until something fails far away.
+// Functions whose doc comment says what happens when the parameter is nil are exempt.
+func (f *file) lintSilentNilInput() {
+	if !f.pkg.enabled("silent-nil-input") || f.pkg.typesInfo == nil {
+		return
+	}
+	for _, decl := range f.f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || fn.Type.Results == nil {
+			continue
+		}
+		obj, ok := f.pkg.typesInfo.Defs[fn.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		sig := obj.Type().(*types.Signature)
+		results := sig.Results()
+		errIndex := -1
+		for i := 0; i < results.Len(); i++ {
+			if types.Identical(results.At(i).Type(), errorType) {
+				errIndex = i
+			}
+		}
+		constructor := errIndex < 0 && results.Len() == 1 && strings.HasPrefix(fn.Name.Name, "New")
+		if errIndex < 0 && !constructor {
+			continue
+		}
+		for _, stmt := range fn.Body.List {
+			ifStmt, ok := stmt.(*ast.IfStmt)
+			if !ok || ifStmt.Init != nil || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+				continue
+			}
+			param := f.nilComparedParam(ifStmt.Cond, sig.Params())
+			if param == nil || fn.Doc != nil && strings.Contains(fn.Doc.Text(), param.Name()+" is nil") {
+				continue
+			}
+			ret, ok := ifStmt.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != results.Len() {
+				continue
+			}
+			// A function that means to do this can say so in its doc comment,
+			// so both cases are reported by default.
+			var p *Problem
+			switch {
+			case constructor && isIdent(ret.Results[0], "nil"):
+				p = f.errorf(ret, 0.8, category("errors"), "%s returns nil when %s is nil, which callers are unlikely to check; return an error or panic instead", fn.Name.Name, param.Name())
+			case errIndex >= 0 && isIdent(ret.Results[errIndex], "nil"):
+				p = f.errorf(ret, 0.9, category("errors"), "%s returns a nil error when %s is nil, hiding the mistake from callers; return an error instead", fn.Name.Name, param.Name())
+			default:
+				continue
+			}
+			p.Related = append(p.Related, f.related(ifStmt.Cond, "%s is checked for nil here", param.Name()))
+		}
+	}
+}
+
+// nilComparedParam returns the parameter among params that cond compares
+// to nil, as in p == nil, or nil if cond is not such a comparison.
+// Only pointer, interface and channel parameters count: a nil slice, map or
+// function is usually a valid empty value rather than a mistake.
+func (f *file) nilComparedParam(cond ast.Expr, params *types.Tuple) *types.Var {
+	be, ok := astutil.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || be.Op != token.EQL {
+		return nil
+	}
+	x := be.X
+	if isIdent(x, "nil") {
+		x = be.Y
+	} else if !isIdent(be.Y, "nil") {
+		return nil
+	}
+	id, ok := astutil.Unparen(x).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	obj := f.pkg.typesInfo.Uses[id]
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) != obj {
+			continue
+		}
+		switch obj.Type().Underlying().(type) {
+		case *types.Pointer, *types.Interface, *types.Chan:
+			return params.At(i)
+		}
+		return nil
+	}
+	return nil
+}
+
+// lintUnused examines the imports and unexported declarations of the package.
+// It complains about imports that a file doesn't use, offering to remove them,
+// and about unexported package-level functions, types, variables, constants
+// and struct fields that nothing uses, or that only other unused declarations use.
+func (p *pkg) lintUnused() {
+	if p.typesInfo == nil || p.typesPkg == nil {
+		return
+	}
+	files := p.sortedFiles()
+	if p.enabled("unused-imports") {
+		for _, f := range files {
+			f.lintUnusedImports()
+		}
+	}
+	if p.enabled("unused-declarations") {
+		p.lintUnusedDecls(files)
+	}
+}
+
+// lintUnusedImports reports the imports that the file doesn't refer to.
+func (f *file) lintUnusedImports() {
+	used := make(map[string]bool)
+	ast.Inspect(f.f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			switch obj := f.pkg.typesInfo.Uses[id].(type) {
+			case *types.PkgName:
+				used[obj.Imported().Path()] = true
+			case nil:
+				// Without type information, go by the name.
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	for _, decl := range f.f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		var unused []*ast.ImportSpec
+		for _, spec := range gd.Specs {
+			is := spec.(*ast.ImportSpec)
+			name := f.importName(is)
+			path, _ := strconv.Unquote(is.Path.Value)
+			if name == "_" || name == "." || path == "C" || used[path] || used[name] {
+				continue
+			}
+			unused = append(unused, is)
+		}
+		for i, is := range unused {
+			p := f.errorf(is, 1, category("imports"), "%s is imported but not used", is.Path.Value)
+			if len(unused) < len(gd.Specs) {
+				start, end := f.lineRange(is)
+				p.Edits = []Edit{f.edit(start, end, "")}
+				continue
+			}
+			// Remove the whole declaration rather than leave an empty one,
+			// with the fix of the first of its imports.
+			if i == 0 {
+				start, end := f.lineRange(gd)
+				p.Edits = []Edit{f.edit(start, f.overBlankLine(start, end), "")}
+			}
+		}
+	}
+}
+
+// overBlankLine returns end, or the start of the next line if the lines
+// before start and from end are both blank, so that removing the text
+// between start and the result doesn't leave two blank lines in a row.
+func (f *file) overBlankLine(start, end token.Pos) token.Pos {
+	s, e := f.fset.Position(start).Offset, f.fset.Position(end).Offset
+	if s >= 2 && f.src[s-2] == '\n' && e < len(f.src) && f.src[e] == '\n' {
+		return end + 1
+	}
+	return end
+}
+
+// lineRange returns the start of the first line of n
+// and the start of the line after its last line.
+func (f *file) lineRange(n ast.Node) (start, end token.Pos) {
+	tf := f.fset.File(n.Pos())
+	start = tf.LineStart(tf.Line(n.Pos()))
+	last := tf.Line(n.End())
+	if last == tf.LineCount() {
+		return start, token.Pos(tf.Base() + tf.Size())
+	}
+	return start, tf.LineStart(last + 1)
+}
+
+// lintUnusedDecls reports the unexported package-level declarations
+// that are not used, even transitively, by the package's exported
+// declarations, methods, init or main functions, and the unexported
+// struct fields that are never referred to.
+func (p *pkg) lintUnusedDecls(files []*file) {
+	candidate := func(obj types.Object) bool {
+		if obj == nil || obj.Exported() || obj.Name() == "_" || obj.Parent() != p.typesPkg.Scope() {
+			return false
+		}
+		if _, ok := obj.(*types.Func); ok && (obj.Name() == "init" || obj.Name() == "main" && p.main) {
+			return false
+		}
+		return true
+	}
+
+	// Record which declarations use which candidates, and which declarations
+	// are roots that are used from outside or run on their own.
+	users := make(map[types.Object][]types.Object)
+	decls := make(map[types.Object]*file)
+	var live []types.Object
+	usedFields := make(map[types.Object]bool)
+	unkeyed := make(map[types.Type]bool)
+	for _, f := range files {
+		for _, decl := range f.f.Decls {
+			var owners []types.Object
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				owners = append(owners, p.typesInfo.Defs[decl.Name])
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						for _, id := range spec.Names {
+							owners = append(owners, p.typesInfo.Defs[id])
+						}
+					case *ast.TypeSpec:
+						owners = append(owners, p.typesInfo.Defs[spec.Name])
+					}
+				}
+			}
+			for _, owner := range owners {
+				if candidate(owner) {
+					decls[owner] = f
+				} else {
+					live = append(live, owner)
+				}
+			}
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.Ident:
+					obj := p.typesInfo.Uses[n]
+					if v, ok := obj.(*types.Var); ok && v.IsField() {
+						usedFields[v] = true
+					}
+					if candidate(obj) {
+						for _, owner := range owners {
+							if owner != obj {
+								users[obj] = append(users[obj], owner)
+							}
+						}
+					}
+				case *ast.CompositeLit:
+					if len(n.Elts) > 0 {
+						if _, ok := n.Elts[0].(*ast.KeyValueExpr); !ok {
+							if t := p.typeOf(n); t != nil {
+								unkeyed[t] = true
+							}
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	// Propagate liveness from the roots through the uses.
+	uses := make(map[types.Object][]types.Object)
+	for obj, us := range users {
+		for _, u := range us {
+			uses[u] = append(uses[u], obj)
+		}
+	}
+	isLive := make(map[types.Object]bool)
+	for len(live) > 0 {
+		obj := live[len(live)-1]
+		live = live[:len(live)-1]
+		for _, used := range uses[obj] {
+			if !isLive[used] {
+				isLive[used] = true
+				live = append(live, used)
+			}
+		}
+	}
+
+	var dead []types.Object
+	for obj := range decls {
+		if !isLive[obj] {
+			dead = append(dead, obj)
+		}
+	}
+	sort.Slice(dead, func(i, j int) bool { return dead[i].Pos() < dead[j].Pos() })
+	for _, obj := range dead {
+		kind := "var"
+		switch obj.(type) {
+		case *types.Func:
+			kind = "func"
+		case *types.TypeName:
+			kind = "type"
+		case *types.Const:
+			kind = "const"
+		}
+		msg := "%s %s is unused"
+		if len(users[obj]) > 0 {
+			msg = "%s %s is only used by unused declarations"
+		}
+		p.errorfAt(p.fset.Position(obj.Pos()), 0.8, category("unused"), msg, kind, obj.Name())
+	}
+
+	for _, name := range p.typesPkg.Scope().Names() {
+		tn, ok := p.typesPkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || unkeyed[tn.Type()] {
+			continue
+		}
+		st, ok := tn.Type().Underlying().(*types.Struct)
+		if !ok {
+			continue
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			field := st.Field(i)
+			// Tagged fields are usually read by reflection, as by encoding/json.
+			if field.Exported() || field.Anonymous() || field.Name() == "_" || st.Tag(i) != "" || usedFields[field] {
+				continue
+			}
+			p.errorfAt(p.fset.Position(field.Pos()), 0.7, category("unused"), "field %s of %s is unused", field.Name(), name)
+		}
+	}
+}
+
+// typedRules lists the rules that rely on type information, and whether
+// they use it within function bodies. The others only use the types of
+// declarations, such as those of parameters.
+var typedRules = []struct {
+	name   string
+	bodies bool
+}{
+	{"nil-deref", true},
+	{"ignored-errors", true},
+	{"redundant-nil-check", true},
+	{"duplicate-condition", true},
+	{"slice-bounds", true},
+	{"dead-branch", true},
+	{"error-message-prefix", false},
+	{"error-strings", true},
+	{"error-wrapping", true},
+	{"unexported-param", false},
+	{"signature-complexity", false},
+	{"getters", false},
+	{"naked-returns", true},
+	{"shadowing", true},
+	{"shadowed-names", true},
+	{"silent-nil-input", true},
+	{"unused-imports", true},
+	{"unused-declarations", true},
+}
+
+// lintTypeErrors reports the errors found by type-checking the package,
+// if the Linter is configured to, since the rules in typedRules
+// see partial information about a package that doesn't type-check.
+// It follows them with the rules that may be affected: those using types
+// within function bodies if all the errors are within bodies, or else all.
+func (p *pkg) lintTypeErrors() {
+	if !p.linter.ReportTypeErrors || len(p.typeErrors) == 0 {
+		return
+	}
+	files := p.sortedFiles()
+	inBodies := true
+	for _, e := range p.typeErrors {
+		conf := 1.0
+		if e.Soft {
+			// The package is well-formed, and types are known everywhere.
+			conf = 0.8
+		}
+		p.errorfAt(p.fset.Position(e.Pos), conf, category("typechecking"), "%s", e.Msg)
+		if !p.inFuncBody(e.Pos) {
+			inBodies = false
+		}
+	}
+	var affected []string
+	for _, rule := range typedRules {
+		if p.enabled(rule.name) && (rule.bodies || !inBodies) {
+			affected = append(affected, rule.name)
+		}
+	}
+	if len(affected) > 0 {
+		p.errorfAt(p.fset.Position(files[0].f.Name.Pos()), 1, category("typechecking"),
+			"package %s has %d type-checking errors; results of these rules may be incomplete: %s",
+			files[0].f.Name.Name, len(p.typeErrors), strings.Join(affected, ", "))
+	}
+}
+
+// inFuncBody reports whether pos is within the body of a function declaration.
+func (p *pkg) inFuncBody(pos token.Pos) bool {
+	for _, f := range p.files {
+		for _, decl := range f.f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil && fn.Body.Pos() <= pos && pos < fn.Body.End() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// importPath returns the import path of the package, as far as it can be told
+// from its directory, or "" if it can't; and whether the package is in the
+// standard library.
+func (p *pkg) importPath() (path string, std bool) {
+	dir := p.dir()
+	if dir == "" {
+		return "", false
+	}
+	if rel, ok := relPath(filepath.Join(build.Default.GOROOT, "src"), dir); ok {
+		return rel, true
+	}
+	if m := p.module(); m != nil && m.path != "" {
+		if rel, ok := relPath(m.dir, dir); ok {
+			if rel == "" {
+				return m.path, false
+			}
+			return m.path + "/" + rel, false
+		}
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		if rel, ok := relPath(filepath.Join(gopath, "src"), dir); ok && rel != "" {
+			return rel, false
+		}
+	}
+	return "", false
+}
+
+// relPath returns the slash-separated path of dir relative to root,
+// and whether dir is inside root at all.
+func relPath(root, dir string) (string, bool) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if rel == "." {
+		return "", true
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// internalRoot reports whether path is an internal package, and if so
+// returns the path of the tree allowed to import it, which is "" for
+// the internal packages of the standard library.
+func internalRoot(path string) (root string, ok bool) {
+	if strings.HasSuffix(path, "/internal") {
+		return strings.TrimSuffix(path, "/internal"), true
+	}
+	if i := strings.LastIndex(path, "/internal/"); i >= 0 {
+		return path[:i], true
+	}
+	if path == "internal" || strings.HasPrefix(path, "internal/") {
+		return "", true
+	}
+	return "", false
+}
+
+// lintImportPaths examines the import paths of the package.
+// It complains about imports of internal packages that the package
+// may not import, about explicit imports of vendor/ paths, and about
+// imports of packages whose package comment says they are deprecated.
+func (p *pkg) lintImportPaths() {
+	internal, vendor, deprecated := p.enabled("internal-imports"), p.enabled("vendor-imports"), p.enabled("deprecated-imports")
+	if !internal && !vendor && !deprecated {
+		return
+	}
+	self, std := p.importPath()
+	type note struct {
+		doc  *ast.CommentGroup // package comment holding the note
+		text string
+	}
+	notes := make(map[string]*note) // deprecation notes by import path
+	for _, f := range p.sortedFiles() {
+		for _, is := range f.f.Imports {
+			path, err := strconv.Unquote(is.Path.Value)
+			if err != nil || path == "C" {
+				continue
+			}
+			if root, ok := internalRoot(path); internal && ok {
+				var allowed bool
+				switch {
+				case root == "":
+					allowed = std
+				case self == "":
+					// Without knowing our own path, give the benefit of the doubt.
+					allowed = true
+				default:
+					allowed = self == root || strings.HasPrefix(self, root+"/")
+				}
+				if !allowed {
+					f.errorf(is, 1, category("imports"), "use of internal package %s not allowed", path)
+					continue
+				}
+			}
+			if i := strings.LastIndex("/"+path, "/vendor/"); vendor && i >= 0 && !std {
+				f.errorf(is, 0.9, category("imports"), "import of vendored path %s; import %q instead", path, path[i+len("vendor/"):])
+				continue
+			}
+			if !deprecated {
+				continue
+			}
+			n, ok := notes[path]
+			if !ok {
+				if doc, text := deprecationNote(p.fset, path, filepath.Dir(f.filename)); doc != nil {
+					n = &note{doc, text}
+				}
+				notes[path] = n
+			}
+			if n != nil {
+				problem := f.errorf(is, 0.8, category("imports"), "package %s is deprecated: %s", path, n.text)
+				problem.Related = append(problem.Related, f.related(n.doc, "package %s deprecated here", path))
+			}
+		}
+	}
+}
+
+// deprecationNote returns the package comment of the package with the
+// given import path, as found from srcDir, and its "Deprecated:" paragraph,
+// or nil if the package isn't deprecated or can't be found.
+// The package's files are parsed into fset.
+func deprecationNote(fset *token.FileSet, path, srcDir string) (*ast.CommentGroup, string) {
+	bp, err := build.Import(path, srcDir, build.FindOnly)
+	if err != nil {
+		return nil, ""
+	}
+	pkgs, err := parser.ParseDir(fset, bp.Dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		return nil, ""
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if f.Doc == nil {
+				continue
+			}
+			for _, para := range strings.Split(f.Doc.Text(), "\n\n") {
+				if strings.HasPrefix(para, "Deprecated: ") {
+					return f.Doc, strings.Join(strings.Fields(strings.TrimPrefix(para, "Deprecated: ")), " ")
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// lintMagicNumbers examines numeric literals in comparisons, arithmetic
+// and the sizes passed to make. It complains about those other than 0, 1,
+// -1, 2 and the Linter's AllowedNumbers, suggesting a named constant.
+// Constant declarations, package-level variables, indexes and test files are exempt.
+func (f *file) lintMagicNumbers() {
+	if !f.pkg.enabled("magic-numbers") || f.isTest() {
+		return
+	}
+	allowed := []constant.Value{constant.MakeInt64(0), constant.MakeInt64(1), constant.MakeInt64(-1), constant.MakeInt64(2)}
+	for _, s := range f.pkg.linter.AllowedNumbers {
+		s = strings.TrimSpace(s)
+		neg := strings.HasPrefix(s, "-")
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+		for _, tok := range []token.Token{token.INT, token.FLOAT} {
+			if v := constant.MakeFromLiteral(s, tok, 0); v.Kind() != constant.Unknown {
+				if neg {
+					v = constant.UnaryOp(token.SUB, v, 0)
+				}
+				allowed = append(allowed, v)
+				break
+			}
+		}
+	}
+	check := func(e ast.Expr, context string) {
+		e = astutil.Unparen(e)
+		num, sign := e, ""
+		if u, ok := e.(*ast.UnaryExpr); ok && (u.Op == token.SUB || u.Op == token.ADD) {
+			if u.Op == token.SUB {
+				sign = "-"
+			}
+			e = astutil.Unparen(u.X)
+		}
+		lit, ok := e.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT && lit.Kind != token.FLOAT {
+			return
+		}
+		v := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+		if sign != "" {
+			v = constant.UnaryOp(token.SUB, v, 0)
+		}
+		for _, a := range allowed {
+			if constant.Compare(v, token.EQL, a) {
+				return
+			}
+		}
+		f.errorf(num, 0.8, category("magic-numbers"), "magic number %s%s in %s; consider a named constant", sign, lit.Value, context)
+	}
+
+	var walk func(n ast.Node) bool
+	walk = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			return n.Tok != token.CONST
+		case *ast.IndexExpr:
+			ast.Inspect(n.X, walk)
+			return false
+		case *ast.SliceExpr:
+			ast.Inspect(n.X, walk)
+			return false
+		case *ast.BinaryExpr:
+			context := "arithmetic"
+			switch n.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+				context = "a comparison"
+			case token.LAND, token.LOR:
+				return true
+			}
+			check(n.X, context)
+			check(n.Y, context)
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN && n.Tok != token.DEFINE {
+				check(n.Rhs[0], "arithmetic")
+			}
+		case *ast.CallExpr:
+			if isIdent(n.Fun, "make") && len(n.Args) > 1 {
+				for _, arg := range n.Args[1:] {
+					check(arg, "the size passed to make")
+				}
+			}
+		}
+		return true
+	}
+	for _, decl := range f.f.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+			// Package-level variables name their values already.
+			continue
+		}
+		ast.Inspect(decl, walk)
+	}
+}
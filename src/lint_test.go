@@ -0,0 +1,295 @@
+// Copyright (c) 2013 The Go Authors. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file or at
+// https://developers.google.com/open-source/licenses/bsd.
+
+package lint
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var lintMatch = flag.String("lint.match", "", "restrict testdata matches to this pattern")
+
+// TestAll lints each file in testdata, and each directory there as a package,
+// and checks the problems found against the instructions in their comments:
+//
+//	CATEGORIES a b    only problems of these categories are checked;
+//	                  with typechecking, type-checking errors are reported
+//	DISABLE r s       the rules r and s are not run on the package
+//	CONFIDENCE 0.8    only problems of at least this confidence are checked
+//	MATCH /regexp/    a problem on this line has text matching regexp
+//	MATCH:12 /re/     the same, for line 12
+//	NOTE /regexp/     a problem has a related position on this line with matching text
+//
+// Every checked problem must be matched by an instruction. If a file has a
+// golden file beside it, named after it with a .golden suffix, applying the
+// edits of its problems must give the contents of the golden file.
+func TestAll(t *testing.T) {
+	rx, err := regexp.Compile(*lintMatch)
+	if err != nil {
+		t.Fatalf("Bad -lint.match value %q: %v", *lintMatch, err)
+	}
+
+	baseDir := "testdata"
+	fis, err := os.ReadDir(baseDir)
+	if err != nil {
+		t.Fatalf("os.ReadDir: %v", err)
+	}
+	if len(fis) == 0 {
+		t.Fatalf("no files in %v", baseDir)
+	}
+	for _, fi := range fis {
+		if !rx.MatchString(fi.Name()) || strings.HasSuffix(fi.Name(), ".golden") {
+			continue
+		}
+		filenames := []string{filepath.Join(baseDir, fi.Name())}
+		if fi.IsDir() {
+			filenames, err = filepath.Glob(filepath.Join(baseDir, fi.Name(), "*.go"))
+			if err != nil {
+				t.Fatalf("filepath.Glob: %v", err)
+			}
+		}
+		lintTestPackage(t, filenames)
+	}
+}
+
+// lintTestPackage lints the files of a package and checks the problems found
+// against their instructions.
+func lintTestPackage(t *testing.T, filenames []string) {
+	files := make(map[string][]byte)
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("Failed reading %s: %v", filename, err)
+		}
+		files[filename] = src
+	}
+	l := &Linter{Disabled: make(map[string]bool)}
+	categories := make(map[string]map[string]bool)
+	minConfidence := make(map[string]float64)
+	ins := make(map[string][]instruction)
+	for _, filename := range filenames {
+		categories[filename], minConfidence[filename], ins[filename] = parseInstructions(t, filename, files[filename], l.Disabled)
+		if categories[filename]["typechecking"] {
+			l.ReportTypeErrors = true
+		}
+	}
+	ps, err := l.LintFiles(files)
+	if err != nil {
+		t.Errorf("Linting %v: %v", filenames, err)
+		return
+	}
+
+	for _, filename := range filenames {
+		categories, minConfidence, ins := categories[filename], minConfidence[filename], ins[filename]
+		if ins == nil {
+			t.Errorf("Test file %v does not have instructions", filename)
+			continue
+		}
+
+		// The problems in this file that the instructions are about.
+		var checked []Problem
+		for _, p := range ps {
+			if p.Position.Filename == filename && categories[p.Category] && p.Confidence >= minConfidence {
+				checked = append(checked, p)
+			}
+		}
+		// A fix may edit other files of the package too.
+		var edits []Edit
+		for _, p := range ps {
+			for _, e := range p.Edits {
+				if e.Pos.Filename == filename && categories[p.Category] {
+					edits = append(edits, e)
+				}
+			}
+		}
+
+		for _, in := range ins {
+			ok := false
+			if in.Note {
+				// The problem may be in another file of the package.
+				for _, p := range ps {
+					for _, r := range p.Related {
+						if categories[p.Category] && r.Position.Filename == filename && r.Position.Line == in.Line && in.Match.MatchString(r.Text) {
+							ok = true
+						}
+					}
+				}
+				if !ok {
+					t.Errorf("Lint failed at %s:%d; no related position matching /%v/", filename, in.Line, in.Match)
+				}
+				continue
+			}
+			for i, p := range checked {
+				if p.Position.Line != in.Line || !in.Match.MatchString(p.Text) {
+					continue
+				}
+				// remove this problem from checked
+				copy(checked[i:], checked[i+1:])
+				checked = checked[:len(checked)-1]
+				ok = true
+				break
+			}
+			if !ok {
+				t.Errorf("Lint failed at %s:%d; /%v/ did not match", filename, in.Line, in.Match)
+			}
+		}
+		for _, p := range checked {
+			t.Errorf("Unexpected problem at %s:%d: %v", filename, p.Position.Line, p.Text)
+		}
+
+		golden, err := os.ReadFile(filename + ".golden")
+		if err != nil {
+			if len(edits) > 0 && !os.IsNotExist(err) {
+				t.Errorf("Failed reading golden file: %v", err)
+			}
+			continue
+		}
+		got, err := applyEdits(files[filename], edits)
+		if err != nil {
+			t.Errorf("Applying the edits to %s: %v", filename, err)
+		} else if !bytes.Equal(got, golden) {
+			t.Errorf("Applying the edits to %s gives\n%s\nwant\n%s", filename, got, golden)
+		}
+	}
+}
+
+// TestErrorConstructors checks that the messages passed to the error
+// constructors configured in the Linter are checked like those of errors.New.
+func TestErrorConstructors(t *testing.T) {
+	const src = `// Package pkg ...
+package pkg
+
+func newError(format string, args ...interface{}) error { return nil }
+
+func f() error {
+	if true {
+		return newError("Bad thing.")
+	}
+	return newError("bad %s", "thing")
+}
+`
+	l := &Linter{ErrorConstructors: []string{"pkg.newError"}}
+	ps, err := l.Lint("pkg.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range ps {
+		if p.Category == "errors" {
+			got = append(got, fmt.Sprintf("%d: %s", p.Position.Line, p.Text))
+		}
+	}
+	want := []string{"8: error strings should not be capitalized or end with punctuation or a newline"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Lint gives problems\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+type instruction struct {
+	Line  int            // the line number this applies to
+	Match *regexp.Regexp // what pattern to match
+	Note  bool           // whether the pattern is for a related position
+}
+
+// parseInstructions parses instructions from the comments in a Go source file.
+// It returns the categories of problems to check, the minimum confidence of
+// the problems to check, and the instructions, or nil if none were parsed.
+// The rules to disable are added to disabled.
+func parseInstructions(t *testing.T, filename string, src []byte, disabled map[string]bool) (map[string]bool, float64, []instruction) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Test file %v does not parse: %v", filename, err)
+	}
+	categories := make(map[string]bool)
+	minConfidence := 0.0
+	var ins []instruction
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			ln := fset.Position(c.Pos()).Line
+			line := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			switch {
+			case strings.HasPrefix(line, "CATEGORIES "):
+				for _, name := range strings.Fields(strings.TrimPrefix(line, "CATEGORIES ")) {
+					categories[name] = true
+				}
+				if ins == nil {
+					// so our return value will be non-nil
+					ins = make([]instruction, 0)
+				}
+			case strings.HasPrefix(line, "DISABLE "):
+				for _, name := range strings.Fields(strings.TrimPrefix(line, "DISABLE ")) {
+					disabled[name] = true
+				}
+			case strings.HasPrefix(line, "CONFIDENCE "):
+				minConfidence, err = strconv.ParseFloat(strings.TrimPrefix(line, "CONFIDENCE "), 64)
+				if err != nil {
+					t.Fatalf("Bad confidence at %v:%d: %v", filename, ln, err)
+				}
+			case strings.HasPrefix(line, "MATCH"), strings.HasPrefix(line, "NOTE"):
+				rx, err := extractPattern(line)
+				if err != nil {
+					t.Fatalf("At %v:%d: %v", filename, ln, err)
+				}
+				matchLine := ln
+				if i := strings.Index(line, ":"); i >= 0 && i < strings.Index(line, "/") {
+					// This is a match for a different line.
+					lns := line[i+1 : strings.Index(line, " ")]
+					matchLine, err = strconv.Atoi(lns)
+					if err != nil {
+						t.Fatalf("Bad match line number %q at %v:%d: %v", lns, filename, ln, err)
+					}
+				}
+				ins = append(ins, instruction{
+					Line:  matchLine,
+					Match: rx,
+					Note:  strings.HasPrefix(line, "NOTE"),
+				})
+			}
+		}
+	}
+	return categories, minConfidence, ins
+}
+
+// extractPattern returns the regular expression between the first and last slash of line.
+func extractPattern(line string) (*regexp.Regexp, error) {
+	a, b := strings.Index(line, "/"), strings.LastIndex(line, "/")
+	if a == -1 || a == b {
+		return nil, fmt.Errorf("malformed pattern: %s", line)
+	}
+	rx, err := regexp.Compile(line[a+1 : b])
+	if err != nil {
+		return nil, err
+	}
+	return rx, nil
+}
+
+// applyEdits returns src with the edits applied. The edits must not overlap.
+func applyEdits(src []byte, edits []Edit) ([]byte, error) {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].Pos.Offset < edits[j].Pos.Offset })
+	var out []byte
+	last := 0
+	for _, e := range edits {
+		if e.Pos.Offset < last || e.End.Offset < e.Pos.Offset || e.End.Offset > len(src) {
+			return nil, fmt.Errorf("overlapping or invalid edit at %v", e.Pos)
+		}
+		out = append(out, src[last:e.Pos.Offset]...)
+		out = append(out, e.New...)
+		last = e.End.Offset
+	}
+	return append(out, src[last:]...), nil
+}
@@ -0,0 +1,105 @@
+// Test of functions with a high cyclomatic or cognitive complexity.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES complexity
+// DISABLE signature-complexity
+
+// A flat switch has many paths, but is easy to follow.
+func verbName(verb rune) string { // MATCH /func verbName has a cyclomatic complexity of 17, more than 15; consider splitting it/
+	switch verb {
+	case 'b':
+		return "binary"
+	case 'c':
+		return "char"
+	case 'd':
+		return "decimal"
+	case 'e':
+		return "exponent"
+	case 'f':
+		return "float"
+	case 'g':
+		return "general"
+	case 'o':
+		return "octal"
+	case 'O':
+		return "octal with prefix"
+	case 'p':
+		return "pointer"
+	case 'q':
+		return "quoted"
+	case 's':
+		return "string"
+	case 't':
+		return "bool"
+	case 'U':
+		return "unicode"
+	case 'v':
+		return "value"
+	case 'x':
+		return "hex"
+	case 'X':
+		return "upper hex"
+	}
+	return ""
+}
+
+// Nesting has few paths, but each level makes it harder to follow.
+func nested(a, b, c, d, e, f bool) int { // MATCH /func nested has a cognitive complexity of 21, more than 15; consider reducing its nesting or splitting it/
+	if a {
+		if b {
+			if c {
+				if d {
+					if e {
+						if f {
+							return 1
+						}
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+
+type scanner struct{ buf []byte }
+
+// Loops, labeled jumps, else branches and mixed operators all add up.
+func (s *scanner) scan(verbs string) int { // MATCH /method scanner.scan has a cognitive complexity of 21, more than 15/
+	n := 0
+outer:
+	for i := 0; i < len(verbs); i++ {
+		for _, b := range s.buf {
+			if b == verbs[i] && i > 0 || b == 0 {
+				continue outer
+			} else if b == ' ' {
+				n++
+			} else {
+				for j := 0; j < n; j++ {
+					if s.buf[j] == b {
+						break outer
+					}
+				}
+				n--
+			}
+		}
+	}
+	return n
+}
+
+// At the limits, nothing is reported.
+func limits(a, b, c, d, e bool) int {
+	if a {
+		if b {
+			if c {
+				if d {
+					if e {
+						return 1
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+// Test of conditions decided by an earlier check.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES dead-code
+
+func invalidSlices(slice2 []int, p *int) int {
+	if slice2 == nil { // NOTE /the earlier check that decides slice2 == nil/
+		return 0
+	}
+	if slice2 == nil { // MATCH /condition slice2 == nil is always false here because of the check on line 9, so this branch is never taken/
+		return 1
+	}
+	if p != nil {
+		println(*p)
+	} else if p != nil { // MATCH /condition p != nil is always false/
+		println("never")
+	}
+	return 2
+}
+
+func lengths(s []int) {
+	if len(s) > 2 {
+		if len(s) == 0 { // MATCH /condition len\(s\) == 0 is always false/
+			return
+		}
+		if len(s) >= 3 {
+			println(s[2])
+		} else { // MATCH /condition len\(s\) >= 3 is always true here because of the check on line 24, so the else branch is never taken/
+			return
+		}
+	}
+}
+
+// Checks that narrow nothing leave nothing to decide later conditions.
+func unnarrowed(s, t []int) {
+	if len(s) != 3 {
+		println(len(s))
+	}
+	if len(s) >= 0 {
+		println(len(s))
+	}
+	if len(t) == 3 {
+		return
+	}
+	if len(t) > 1 {
+		println(len(t))
+	}
+}
+
+// The err declared in the if statement is a different variable.
+func shadowed(a, b func() error) error {
+	err := a()
+	if err := b(); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
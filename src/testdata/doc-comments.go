@@ -0,0 +1,61 @@
+// Test of the text of doc comments.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES comments
+
+import "errors"
+
+// Scanner reads tokens
+type Scanner struct{} // MATCH:10 /comment on exported type Scanner should be a full sentence ending in a period/
+
+// Reader reads bytes (from a source)
+type Reader struct{} // MATCH:13 /comment on exported type Reader should be a full sentence ending in a period/
+
+// Writer writes bytes "to a sink."
+type Writer struct{}
+
+// Example prints a greeting, as in
+//
+//	Example()
+func Example() {}
+
+// Buffer buffer.
+type Buffer struct{} // MATCH:24 /comment on exported type Buffer only repeats its name; it should describe what Buffer is or does/
+
+// A Token token.
+type Token struct{} // MATCH:27 /comment on exported type Token only repeats its name; it should describe what Token is or does/
+
+// Scan scans.
+func (s *Scanner) Scan() {}
+
+// Split sets the split function of the scanner.
+//
+func (s *Scanner) Split() {} // MATCH:34 /comment on exported method Scanner.Split should not end with an empty line/
+
+// Buffered returns the number of bytes buffered
+//
+func (s *Scanner) Buffered() int { // MATCH:37 /comment on exported method Scanner.Buffered should be a full sentence ending in a period/
+	return 0 // MATCH:38 /comment on exported method Scanner.Buffered should not end with an empty line/
+}
+
+// Open opens the named file f and returns its size.
+func Open(name string) (f *Reader, size int, err error) { // MATCH:43 /comment on exported function Open should describe its named result err/
+	return nil, 0, errors.New("pkg: not implemented")
+}
+
+// Stat returns information about the named file, or an error.
+func Stat(name string) (n int, ok bool, err error) { // MATCH:48 /comment on exported function Stat should describe its named results n, ok/
+	return 0, false, nil
+}
+
+// Create creates the named file, and returns the file f and any error encountered.
+func Create(name string) (f *Reader, err error) {
+	return nil, nil
+}
+
+// Size returns the size n of the named file.
+func Size(name string) (n int) {
+	return 0
+}
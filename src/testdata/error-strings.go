@@ -0,0 +1,26 @@
+// Test of the messages passed to error constructors.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES errors
+// DISABLE ignored-errors
+
+import (
+	"errors"
+	e "errors"
+	"fmt"
+)
+
+func messages(err error) {
+	_ = errors.New("Bad thing")   // MATCH /error strings should not be capitalized or end with punctuation or a newline/
+	_ = errors.New("bad thing.")  // MATCH /error strings should not be capitalized/
+	_ = fmt.Errorf("bad thing\n") // MATCH /error strings should not be capitalized/
+	_ = e.New("Renamed import")   // MATCH /error strings should not be capitalized/
+	_ = errors.New("bufio.Scanner: token too long")
+	_ = fmt.Errorf("read %s: %w", "x", err)
+	_ = fmt.Errorf("read: %w, %w", err, err) // MATCH /fmt.Errorf call has 2 %w verbs; an error should wrap at most one other/
+	_ = newError("Not a constructor.")
+}
+
+func newError(msg string) error { return errors.New(msg) }
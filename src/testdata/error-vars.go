@@ -0,0 +1,27 @@
+// Test of the documentation, names and messages of sentinel error variables.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES comments errors naming
+// CONFIDENCE 0.8
+
+import "errors"
+
+var ( // MATCH /exported error variables in this block should have a comment on the block/
+	ErrShort = errors.New("pkg: short buffer")
+	ErrLong  = errors.New("long buffer") // MATCH /message of exported error ErrLong should be prefixed by the package name, as in "pkg: long buffer"/
+)
+
+// Errors returned when something else goes wrong.
+var (
+	ErrOther  = errors.New("pkg: other")
+	ErrDotted = errors.New("pkg.Read: dotted")
+	errQuiet  = errors.New("quiet")
+)
+
+// Errors with names not of the standard form.
+var (
+	BadLength = errors.New("pkg: bad length") // MATCH /error var BadLength should have name of the form ErrFoo/
+	badState  = errors.New("bad state")       // MATCH /error var badState should have name of the form errFoo/
+)
@@ -0,0 +1,28 @@
+// Test of errors formatted into other errors without wrapping them.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+type parseError struct{}
+
+func (*parseError) Error() string { return "parse error" }
+
+func wrap(err error, pe *parseError, name string) []error {
+	return []error{
+		fmt.Errorf("reading %s: %v", name, err), // MATCH /error operand is formatted with %v; use %w to let callers unwrap it/
+		fmt.Errorf("parsing: %s", pe),           // MATCH /error operand is formatted with %s/
+		fmt.Errorf("%v and %v", err, pe),        // MATCH /error operand is formatted with %v/
+		fmt.Errorf("wrapped: %w (%v)", err, pe),
+		fmt.Errorf("name %v", name),
+		fmt.Errorf("%[1]v", err),
+		errors.New(err.Error()), // MATCH /errors.New\(err.Error\(\)\) drops the cause/
+		errors.New(name),
+	}
+}
@@ -0,0 +1,66 @@
+// Test of getters named with a Get prefix.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES naming
+
+import "context"
+
+// Sample holds an item.
+type Sample struct {
+	item  string
+	value int
+}
+
+// GetItem returns the item.
+func (s *Sample) GetItem() string { // MATCH /getter Sample.GetItem should be named Item, without the Get prefix/
+	return s.item
+}
+
+// GetSize returns the size of the item.
+func (s *Sample) GetSize(ctx context.Context) int { // MATCH /getter Sample.GetSize should be named Size/
+	return len(s.GetItem())
+}
+
+// GetValue can't be renamed, since Value exists already.
+func (s *Sample) GetValue() int { // MATCH /getter Sample.GetValue should be named Value/
+	return s.value
+}
+
+// Value returns the value.
+func (s *Sample) Value() int {
+	return s.value
+}
+
+// GetPart takes an argument, so it is not a getter.
+func (s *Sample) GetPart(i int) byte {
+	return s.item[i]
+}
+
+// Identifier is implemented by Sample.
+type Identifier interface {
+	GetID() string
+}
+
+// GetID is required by Identifier.
+func (s *Sample) GetID() string {
+	return s.item
+}
+
+// Message looks like a generated protocol buffer message.
+type Message struct {
+	name string
+}
+
+// ProtoMessage marks Message as a protocol buffer message.
+func (*Message) ProtoMessage() {}
+
+// GetName returns the name, as generated for protocol buffers.
+func (m *Message) GetName() string {
+	return m.name
+}
+
+func use(ctx context.Context, s *Sample) string {
+	return s.GetItem() + string(rune(s.GetSize(ctx)))
+}
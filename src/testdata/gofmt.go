@@ -0,0 +1,15 @@
+// Test of files that gofmt would change.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES format
+
+func add(a, b int) int {
+	return a+b // MATCH /file is not gofmt-ed from this line; run gofmt/
+}
+
+// Only the first region that differs is reported.
+func sub(a, b int) int {
+	return a-b
+}
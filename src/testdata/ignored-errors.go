@@ -0,0 +1,47 @@
+// Test of dropped error results.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES errors
+// CONFIDENCE 0.8
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+type sink struct{ n int }
+
+func (s *sink) Write(p []byte) (int, error) {
+	s.n += len(p)
+	return len(p), nil
+}
+
+func writers(w io.Writer, f *os.File, s *sink, b []byte) {
+	w.Write(b) // MATCH /error return value of w.Write is not checked/
+	f.Write(b) // MATCH /error return value of f.Write is not checked/
+	s.Write(b) // MATCH /error return value of s.Write is not checked/
+}
+
+func reader(r io.Reader, buf []byte) int {
+	n, _ := r.Read(buf) // MATCH /error return value of r.Read is assigned to _/
+	return n
+}
+
+func functions(name string) {
+	os.Remove(name)      // MATCH /error return value of os.Remove is not checked/
+	_ = os.Chdir(name)   // MATCH /error return value of os.Chdir is assigned to _/
+	_, _ = os.Open(name) // MATCH /error return value of os.Open is assigned to _/
+}
+
+func allowed(w io.Writer, buf *bytes.Buffer, h hash.Hash, b []byte) {
+	fmt.Println("done")
+	fmt.Fprintln(os.Stderr, "done")
+	buf.Write(b)
+	h.Write(b)
+	fmt.Fprintln(w, "done") // MATCH /error return value of fmt.Fprintln is not checked/
+}
@@ -0,0 +1,18 @@
+// Test of import paths that a package should not use.
+
+// Package imports ...
+package imports
+
+// CATEGORIES imports
+
+import (
+	"io/ioutil" // MATCH /package io/ioutil is deprecated: As of Go 1.16/
+
+	// The imports below are only for their paths.
+	_ "example.com/vendor/golang.org/x/text"  // MATCH /import of vendored path example.com/vendor/golang.org/x/text; import "golang.org/x/text" instead/
+	_ "golang.org/x/lint/src/internal/shared" // allowed: the internal directory is in a parent of this package
+	_ "golang.org/x/tools/internal/event"     // MATCH /use of internal package golang.org/x/tools/internal/event not allowed/
+	_ "internal/fmtsort"                      // MATCH /use of internal package internal/fmtsort not allowed/
+)
+
+var read = ioutil.ReadFile
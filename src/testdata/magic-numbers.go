@@ -0,0 +1,42 @@
+// Test of numeric literals that deserve a named constant.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES magic-numbers
+
+const minAge = 13
+
+var bufSize = 4096
+
+type user struct {
+	age int
+}
+
+func isSaleValid(customer user) bool {
+	if customer.age < 13 { // MATCH /magic number 13 in a comparison; consider a named constant/
+		return false
+	}
+	return customer.age >= minAge
+}
+
+func buffers(n int) ([]byte, []byte, []int) {
+	a := make([]byte, 256) // MATCH /magic number 256 in the size passed to make/
+	b := make([]byte, 0, bufSize)
+	c := make([]int, n, -40) // MATCH /magic number -40 in the size passed to make/
+	return a, b, c
+}
+
+func arithmetic(x int, f float64) (int, float64) {
+	x *= 60 // MATCH /magic number 60 in arithmetic/
+	x = x*2 + 1 - 0
+	return x, f * 1.5 // MATCH /magic number 1.5 in arithmetic/
+}
+
+func indexes(s []int) int {
+	const limit = 100
+	if len(s) > limit {
+		return s[7] + s[3:5][0]
+	}
+	return -1
+}
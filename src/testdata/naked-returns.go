@@ -0,0 +1,46 @@
+// Test of naked returns.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES style
+
+import "strconv"
+
+func parse(s string) (n int, err error) {
+	if s == "" {
+		// MATCH:13 /naked return in func parse returns n, err before any assignment/
+		return // MATCH /naked return in func parse, which is 14 lines long; return the results explicitly/
+	}
+	n, err = strconv.Atoi(s)
+	if err != nil {
+		return // MATCH /naked return in func parse, which is 14 lines long/
+	}
+	if n > 100 {
+		n = 100
+	}
+	return // MATCH /naked return in func parse, which is 14 lines long/
+}
+
+func short(ok bool) (n int) {
+	if !ok {
+		return // MATCH /naked return in func short returns n before any assignment; return the zero values explicitly/
+	}
+	n = 1
+	return
+}
+
+func blank(s string) (_ int, err error) {
+	if s == "" {
+		// MATCH:36 /naked return in func blank returns err before any assignment/
+		return // MATCH /naked return in func blank, which is 14 lines long/
+	}
+	_, err = strconv.Atoi(s)
+	if err != nil {
+		err = nil
+	}
+	if len(s) > 1 {
+		s = s[1:]
+	}
+	return // MATCH /naked return in func blank, which is 14 lines long/
+}
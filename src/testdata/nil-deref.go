@@ -0,0 +1,104 @@
+// Test of dereferences of values compared to nil.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES nil
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type node struct {
+	val  int
+	next *node
+}
+
+func (n *node) sum() int { return n.val }
+
+func fallThrough(r io.Reader, buf []byte) {
+	if r == nil { // NOTE /r compared to nil here/
+		println("no reader")
+	}
+	r.Read(buf) // MATCH /possible nil method call: r may be nil here after the check on line 22/
+}
+
+func inBranch(n *node) int {
+	if n == nil {
+		return n.val // MATCH /possible nil field access: n is nil here after the check on line 29/
+	}
+	return n.val
+}
+
+func guarded(n *node) int {
+	if n == nil {
+		n = &node{}
+	}
+	if n != nil && n.next != nil {
+		return n.next.val
+	}
+	return n.val
+}
+
+func returned(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.sum()
+}
+
+func a() error { return nil }
+func b() error { return nil }
+
+// The err declared in the if statement is a different variable,
+// so nothing is known about the outer one after it.
+func shadowed() error {
+	err := a()
+	if err != nil {
+		if err := b(); err != nil {
+			return err
+		}
+		return errors.New(err.Error())
+	}
+	return nil
+}
+
+func shadowedNil() error {
+	err := a()
+	if err == nil {
+		if err := b(); err != nil {
+			return errors.New(err.Error())
+		}
+		return errors.New(err.Error()) // MATCH /possible nil method call: err is nil here/
+	}
+	return nil
+}
+
+func fatal(t *testing.T, n *node) int {
+	if n == nil {
+		t.Fatal("no node")
+	}
+	return n.val
+}
+
+func skip(tb testing.TB, n *node) int {
+	if n == nil {
+		tb.Skip("no node")
+	}
+	return n.val
+}
+
+func logged(t *testing.T, n *node) int {
+	if n == nil {
+		t.Log("no node")
+	}
+	return n.val // MATCH /possible nil field access: n may be nil here/
+}
+
+func reset(n *node) int {
+	total := n.val
+	n = nil // NOTE /n set to nil here/
+	return total + n.val // MATCH /possible nil field access: n is nil here after the assignment on line 102/
+}
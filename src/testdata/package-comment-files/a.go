@@ -0,0 +1,4 @@
+// Package pkg tests a package comment in several files.
+package pkg // NOTE:1 /first package comment/
+
+// CATEGORIES comments
@@ -0,0 +1,4 @@
+// Package pkg is documented here too.
+package pkg // MATCH:1 /package pkg has a package comment in 2 files; it should have only one/
+
+// CATEGORIES comments
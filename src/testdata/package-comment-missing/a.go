@@ -0,0 +1,5 @@
+// Test of a package with no package comment.
+
+package pkg // MATCH /package pkg should have a package comment/
+
+// CATEGORIES comments
@@ -0,0 +1,4 @@
+// Package pkg has a second package comment.
+package pkg // MATCH:1 /package comment for pkg should be in doc.go/
+
+// CATEGORIES comments
@@ -0,0 +1,5 @@
+// Helpers for pkg.
+package pkg // MATCH:1 /package comment should be of the form "Package pkg ..."/
+
+// CATEGORIES comments
+// NOTE:1 /another package comment outside doc.go/
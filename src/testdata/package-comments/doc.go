@@ -0,0 +1,4 @@
+// Package pkg tests the package comments of a package with a doc.go file.
+package pkg
+
+// CATEGORIES comments
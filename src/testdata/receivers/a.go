@@ -0,0 +1,67 @@
+// Test of receiver names and kinds across the files of a package.
+
+// Package receivers has types whose methods are spread over two files.
+package receivers
+
+// CATEGORIES naming
+// CONFIDENCE 0.8
+
+import "io"
+
+// Sample uses the same receiver name and kind everywhere, as it should.
+type Sample struct {
+	item string
+}
+
+// Item returns the item.
+func (s *Sample) Item() string {
+	return s.item
+}
+
+// Buffer mixes receiver names and kinds.
+type Buffer struct {
+	data []byte
+}
+
+// Len returns the length of the buffer.
+func (b *Buffer) Len() int { // NOTE /receiver named b here/
+	// NOTE:27 /Buffer.Len declared here/
+	return len(b.data)
+}
+
+// Reset empties the buffer.
+func (b *Buffer) Reset() {
+	b.data = b.data[:0]
+}
+
+// Reader has a receiver name that is too long.
+type Reader struct {
+	r io.Reader
+}
+
+// Read reads from the underlying reader.
+func (reader *Reader) Read(p []byte) (int, error) { // MATCH /receiver name reader should be short/
+	return reader.r.Read(p)
+}
+
+// Stack uses generic and inconsistent receiver names within this file.
+type Stack struct {
+	items []string
+}
+
+// Push adds an item.
+func (this *Stack) Push(item string) { // MATCH /receiver name should be a reflection of its identity/
+	this.items = append(this.items, item)
+}
+
+// Pop removes the last item.
+func (st *Stack) Pop() string {
+	item := st.items[len(st.items)-1]
+	st.items = st.items[:len(st.items)-1]
+	return item
+}
+
+// Peek returns the last item.
+func (sk *Stack) Peek() string { // MATCH /receiver name sk should be consistent with previous receiver name st for Stack/
+	return sk.items[len(sk.items)-1]
+}
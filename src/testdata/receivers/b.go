@@ -0,0 +1,30 @@
+package receivers
+
+// CATEGORIES naming
+// CONFIDENCE 0.8
+
+// SetItem sets the item.
+func (s *Sample) SetItem(item string) {
+	s.item = item
+}
+
+// Bytes returns the contents of the buffer.
+func (buf Buffer) Bytes() []byte { // MATCH /receiver name buf should be consistent with receiver name b used for Buffer in a.go/
+	// MATCH:12 /method Buffer.Bytes has a value receiver, unlike Buffer.Len/
+	return buf.data
+}
+
+// Close does nothing.
+func (reader *Reader) Close() error { // MATCH /receiver name reader should be short/
+	return nil
+}
+
+// Len returns the number of items.
+func (self *Stack) Len() int { // MATCH /receiver name should be a reflection of its identity/
+	return len(self.items)
+}
+
+// Empty reports whether there are no items.
+func (st *Stack) Empty() bool {
+	return len(st.items) == 0
+}
@@ -0,0 +1,111 @@
+// Test of nil checks on slices and maps that change nothing.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES redundant
+
+func lengths(s []int, m map[string]int) int {
+	if s != nil { // MATCH /nil check on s is redundant: every use of it in this block is safe on a nil slice/
+		for _, v := range s {
+			println(v)
+		}
+	}
+	if m == nil || len(m) == 0 { // MATCH /nil check on m is redundant: the length check already covers a nil map/
+		return 0
+	}
+	return len(s)
+}
+
+func writes(m map[string]int) {
+	if m != nil {
+		m["x"] = 1
+	}
+}
+
+func indexed(s []int) int {
+	if s == nil {
+		return -1
+	}
+	return s[1]
+}
+
+func ranged(m map[string]int) (int, bool) {
+	if m == nil {
+		return 0, false
+	}
+	for _, v := range m {
+		println(v)
+	}
+	return 0, true
+}
+
+func sameResult(s []int) int {
+	if s == nil { // MATCH /nil check on s is redundant: every later use of it is safe on a nil slice/
+		return 0
+	}
+	for _, v := range s {
+		println(v)
+	}
+	return len(s)
+}
+
+func moreWork(s []int) {
+	if s == nil {
+		return
+	}
+	for _, v := range s {
+		println(v)
+	}
+	println("done")
+}
+
+func reversed(s []int, m map[string]int) bool {
+	if nil == s || 0 == len(s) { // MATCH /nil check on s is redundant: the length check already covers a nil slice/
+		return false
+	}
+	return m != nil && 0 < len(m) // MATCH /nil check on m is redundant: the length check already covers a nil map/
+}
+
+func otherLength(s, t []int) bool {
+	return s == nil || len(t) == 0
+}
+
+func shadowed(s []int) bool {
+	if s == nil {
+		return false
+	}
+	{
+		s := []int{1}
+		if s == nil || len(s) == 0 { // MATCH /nil check on s is redundant: the length check already covers a nil slice/
+			return false
+		}
+	}
+	return true
+}
+
+// The early return gives different results from the rest of the function,
+// which also indexes the slice.
+func invalidSlices(slice1 []string, slice2 []int) (bool, int) {
+	if slice1 == nil {
+		return false, 0
+	}
+	if slice2 == nil {
+		return false, 0
+	}
+	slice1[1] = ""
+	return true, 1
+}
+
+// The early return skips the assignment to newArgNum.
+func intFromArg(a []interface{}, argNum int) (num int, isInt bool, newArgNum int) {
+	if a == nil {
+		return
+	}
+	newArgNum = argNum
+	if argNum < len(a) {
+		num, isInt = a[argNum].(int)
+		newArgNum = argNum + 1
+	}
+	return
+}
@@ -0,0 +1,19 @@
+// Test of explicit semicolons and runs of blank lines, and of how they
+// combine with the gofmt rule.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES format
+
+func f() int {
+	x := 1; // MATCH /unnecessary semicolon at end of statement/
+	y := 2; z := 3 // MATCH /file is not gofmt-ed from this line/
+	return x + y + z; // MATCH /unnecessary semicolon/
+}
+
+
+
+func g() {} // MATCH:15 /3 consecutive blank lines; use at most one/
+
+func h() { return; } // MATCH /unnecessary semicolon/
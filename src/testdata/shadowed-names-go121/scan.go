@@ -0,0 +1,20 @@
+// Test of declarations that shadow builtins added in Go 1.21.
+
+// Package shadowed ...
+package shadowed
+
+// CATEGORIES shadowing
+
+// Scanner scans.
+type Scanner struct {
+	buf []byte
+	max int
+}
+
+// Buffer sets the initial buffer and the maximum size of the buffer.
+func (s *Scanner) Buffer(buf []byte, max int) { // MATCH /declaration of max shadows the builtin function max/
+	s.buf = buf
+	s.max = max
+}
+
+func clear() {} // MATCH /declaration of clear shadows the builtin function clear/
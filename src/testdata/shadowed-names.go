@@ -0,0 +1,44 @@
+// Test of declarations that shadow predeclared identifiers and imported packages.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES shadowing
+
+import (
+	"strings"
+	str "strconv"
+)
+
+type pkg struct{} // MATCH /declaration of pkg has the same name as its package/
+
+// NOTE:4 /package pkg declared here/
+
+func lengths(len int, copy []byte) int { // MATCH /declaration of len shadows the builtin function len/
+	// MATCH:17 /declaration of copy shadows the builtin function copy/
+	return len
+}
+
+type error struct{} // MATCH /declaration of error shadows the predeclared type error/
+
+func values() {
+	true := false // MATCH /declaration of true shadows the predeclared constant true/
+	_ = true
+	nil := 0 // MATCH /declaration of nil shadows the predeclared constant nil/
+	_ = nil
+}
+
+func imports(s string) string { // NOTE:9 /package strings imported here/
+	strings := strings.Fields(s) // MATCH /declaration of strings shadows the imported package strings/
+	// NOTE:10 /package str imported here/
+	for _, str := range strings { // MATCH /declaration of str shadows the imported package str/
+		return str
+	}
+	return ""
+}
+
+// The module declares no go version, which means Go 1.16, without max and min.
+func bounds(min, max int) int {
+	any := max - min
+	return any
+}
@@ -0,0 +1,77 @@
+// Test of declarations that shadow variables of an enclosing scope.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES shadowing
+
+import "io"
+
+type reader struct {
+	buf []byte
+	rd  io.Reader
+}
+
+func (b *reader) writeBuf(w io.Writer) (int64, error) {
+	n, err := w.Write(b.buf)
+	return int64(n), err
+}
+
+func (b *reader) WriteTo(w io.Writer) (n int64, err error) { // NOTE /shadowed w declared here/
+	// NOTE:20 /shadowed err declared here/
+	n, err = b.writeBuf(w)
+	if err != nil {
+		return
+	}
+
+	if r, ok := b.rd.(io.WriterTo); ok {
+		m, err := r.WriteTo(w) // MATCH /declaration of err shadows the named result err of an enclosing scope, which is used after this block/
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+
+	if w, ok := w.(io.ReaderFrom); ok { // MATCH /declaration of w shadows the parameter w of an enclosing scope, which is used after this block/
+		m, _ := w.ReadFrom(b.rd)
+		n += m
+	}
+	_, err = w.Write(nil)
+	return n, err
+}
+
+func parse(data []byte) error {
+	err := check(data) // NOTE /shadowed err declared here/
+	for i := range data {
+		if err := check(data[i:]); err != nil { // MATCH /declaration of err shadows the variable err of an enclosing scope/
+			return err
+		}
+	}
+	return err
+}
+
+func check(data []byte) error { return nil }
+
+// A shadowed variable not used after the inner block is fine.
+func last(data []byte) error {
+	err := check(data)
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		if err := check(data[i:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Other variables may be shadowed.
+func other(n int) int {
+	total := n
+	for i := 0; i < n; i++ {
+		total := i * 2
+		_ = total
+	}
+	return total
+}
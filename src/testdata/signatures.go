@@ -0,0 +1,39 @@
+// Test of functions with too many parameters or results.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES complexity
+
+func intFromArg(a []interface{}, argNum int) (num int, isInt bool, newArgNum int) { // MATCH /func intFromArg has 3 results, more than 2; consider returning a struct/
+	return 0, false, argNum
+}
+
+func parse(s string) (int, int, error) { // MATCH /func parse has 3 results, more than 2; consider naming the results or returning a struct/
+	return 0, 0, nil
+}
+
+func split(s string) (head, tail string) {
+	return s, ""
+}
+
+// ItemIsEmpty reports whether the item at the given coordinates is empty.
+func ItemIsEmpty(a, b, c, d int) bool { // MATCH /func ItemIsEmpty has 4 adjacent parameters of type int, which callers can easily mix up/
+	return true
+}
+
+func flags(verbose, quiet, force, dryRun bool) {} // MATCH /func flags has 4 adjacent parameters of type bool/
+
+func point(x, y, z int) {}
+
+func broken(a, b int, name string, c, d int) {}
+
+func many(a string, b []byte, c float64, d error, e rune, f interface{}) {} // MATCH /func many has 6 parameters, more than 5; consider grouping them in an options struct/
+
+type grid struct{}
+
+func (g *grid) at(x, y int) (v int, ok bool, err error) { // MATCH /method grid.at has 3 results, more than 2/
+	return 0, false, nil
+}
+
+func (g *grid) set(x, y, z, w int) {} // MATCH /method grid.set has 4 adjacent parameters of type int/
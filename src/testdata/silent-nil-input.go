@@ -0,0 +1,90 @@
+// Test of functions that accept a nil argument without complaint.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES errors
+
+import (
+	"errors"
+	"io"
+)
+
+// Config configures a client.
+type Config struct{ name string }
+
+// Client is a client.
+type Client struct{ cfg *Config }
+
+// NewClient returns a client for the configuration.
+func NewClient(cfg *Config) *Client {
+	if cfg == nil { // NOTE /cfg is checked for nil here/
+		return nil // MATCH /NewClient returns nil when cfg is nil, which callers are unlikely to check; return an error or panic instead/
+	}
+	return &Client{cfg}
+}
+
+// Copy copies from r.
+func Copy(r io.Reader) error {
+	if nil == r {
+		return nil // MATCH /Copy returns a nil error when r is nil, hiding the mistake from callers; return an error instead/
+	}
+	return errors.New("pkg: not implemented")
+}
+
+// Drain drains the channel.
+func Drain(c chan int) (int, error) {
+	if c == nil {
+		return 0, nil // MATCH /Drain returns a nil error when c is nil/
+	}
+	return len(c), nil
+}
+
+// Open opens the configuration. If cfg is nil, the defaults are used.
+func Open(cfg *Config) error {
+	if cfg == nil {
+		return nil
+	}
+	return nil
+}
+
+// Write writes the bytes. A nil slice is an empty one.
+func Write(b []byte) error {
+	if b == nil {
+		return nil
+	}
+	return nil
+}
+
+// Set sets the values.
+func Set(m map[string]int) error {
+	if m == nil {
+		return nil
+	}
+	return nil
+}
+
+// NewTable returns a table of the values.
+func NewTable(m map[string]int) *Client {
+	if m == nil {
+		return nil
+	}
+	return &Client{}
+}
+
+// Walk calls fn.
+func Walk(fn func()) error {
+	if fn == nil {
+		return nil
+	}
+	fn()
+	return nil
+}
+
+// Close returns an error for a nil client.
+func Close(c *Client) error {
+	if c == nil {
+		return errors.New("pkg: nil client")
+	}
+	return nil
+}
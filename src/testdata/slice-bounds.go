@@ -0,0 +1,46 @@
+// Test of constant indexes and slice bounds on slices of known length.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES bounds
+
+func invalidSlices(slice1 []string, slice2 []int) {
+	if slice1 == nil {
+		return
+	}
+	if slice2 == nil { // NOTE /slice2 is known to be nil from here/
+		_ = slice2[:2] // MATCH /slice bounds out of range: slice2 is nil here \(see line 12\)/
+	}
+	slice1[1] = "" // MATCH /index 1 of slice1 is not guarded by a length check; the check on line 9 doesn't ensure len\(slice1\) > 1/
+}
+
+func lengths(s []int) int {
+	if len(s) > 0 { // NOTE /what is known about s comes from here/
+		return s[0] + s[1] // MATCH /index 1 of s is not guarded by a length check/
+	}
+	return s[0] // MATCH /index 0 is out of range: s has at most 0 elements here \(see line 19\)/
+}
+
+func exact(s []int) int {
+	if len(s) == 2 {
+		return s[1] + s[2] // MATCH /index 2 is out of range: s has at most 2 elements here/
+	}
+	return 0
+}
+
+func made() int {
+	x := make([]int, 3)
+	y := []int{1, 2}
+	return x[2] + y[2] // MATCH /index 2 is out of range: y has at most 2 elements here/
+}
+
+// A check that narrows nothing records nothing.
+func unnarrowed(args []string) {
+	if len(args) != 2 {
+		println(len(args))
+	}
+	if len(args) >= 2 {
+		println(args[1])
+	}
+}
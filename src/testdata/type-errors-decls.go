@@ -0,0 +1,12 @@
+// Test of type-checking errors in declarations.
+
+// Package pkg ...
+package pkg // MATCH /package pkg has 1 type-checking errors; results of these rules may be incomplete: nil-deref, ignored-errors, redundant-nil-check, duplicate-condition, slice-bounds, dead-branch, error-message-prefix, error-strings, error-wrapping, unexported-param, signature-complexity, getters, naked-returns, shadowed-names, silent-nil-input, unused-imports, unused-declarations$/
+
+// CATEGORIES typechecking
+// DISABLE shadowing
+
+// Printer prints.
+type Printer struct {
+	out writer // MATCH /undefined: writer/
+}
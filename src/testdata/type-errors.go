@@ -0,0 +1,18 @@
+// Test of type-checking errors within function bodies.
+
+// Package pkg ...
+package pkg // MATCH /package pkg has 2 type-checking errors; results of these rules may be incomplete: nil-deref, ignored-errors, redundant-nil-check, duplicate-condition, slice-bounds, dead-branch, error-strings, error-wrapping, naked-returns, shadowing, shadowed-names, silent-nil-input, unused-imports, unused-declarations$/
+
+// CATEGORIES typechecking
+
+func errorf(format *string, a ...interface{}) error {
+	return doPrintf(format, a) // MATCH /undefined: doPrintf/
+}
+
+func count(s []string) int {
+	n := "0"
+	for range s {
+		n++ // MATCH /invalid operation: n\+\+ \(non-numeric type string\)/
+	}
+	return 0
+}
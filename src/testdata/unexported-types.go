@@ -0,0 +1,39 @@
+// Test of exported functions with unexported types in their signatures.
+
+// Package pkg ...
+package pkg
+
+// CATEGORIES unexported-type-in-api
+
+type user struct{ name string }
+
+// User is an exported user.
+type User struct{ name string }
+
+// Lookup finds a user by name.
+func Lookup(u *user) {} // MATCH /exported func Lookup takes unexported type \*pkg.user, which can be annoying to use/
+
+// LookupAll finds users.
+func LookupAll(us []user, byName map[string]*user) {} // MATCH /exported func LookupAll takes unexported type \[\]pkg.user/
+
+// LookupEach finds users.
+func LookupEach(names []string, us ...user) {} // MATCH /exported func LookupEach takes unexported type pkg.user/
+
+// NewUser returns a new user.
+func NewUser(name string) *user { return &user{name} } // MATCH /exported func NewUser returns unexported type \*pkg.user, which can be annoying to use/
+
+// Copy copies a user.
+func Copy(u *user) *user { // MATCH /exported func Copy takes unexported type \*pkg.user/
+	return u // MATCH:26 /exported func Copy returns unexported type \*pkg.user/
+}
+
+// Rename renames the user.
+func (u *User) Rename(other user) {} // MATCH /exported method Rename takes unexported type pkg.user/
+
+// Rename renames the user, as part of an interface implementation.
+func (u *user) Rename(other user) {}
+
+// Valid reports whether the user is valid.
+func Valid(u User, names []string, ages map[string]int) bool { return true }
+
+func isValid(u user) bool { return true }
@@ -0,0 +1,28 @@
+// Test of unused imports and declarations across the files of a package.
+
+// Package unused ...
+package unused
+
+// CATEGORIES imports unused
+
+import (
+	"fmt"
+	"os" // MATCH /"os" is imported but not used/
+	"strings"
+)
+
+// Exported uses helper, which is declared in b.go.
+func Exported() string {
+	return fmt.Sprint(helper())
+}
+
+func orphan() string { // MATCH /func orphan is unused/
+	return strings.ToUpper(onlyOrphan)
+}
+
+var onlyOrphan = "x" // MATCH /var onlyOrphan is only used by unused declarations/
+
+type config struct {
+	name  string
+	debug bool // MATCH /field debug of config is unused/
+}
@@ -0,0 +1,9 @@
+package unused
+
+// CATEGORIES imports unused
+
+import "io" // MATCH /"io" is imported but not used/
+
+func helper() config {
+	return config{name: "b"}
+}
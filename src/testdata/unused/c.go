@@ -0,0 +1,11 @@
+package unused
+
+// CATEGORIES imports unused
+
+import (
+	"bytes"  // MATCH /"bytes" is imported but not used/
+	"errors" // MATCH /"errors" is imported but not used/
+)
+
+// Other is declared after imports that are all unused.
+func Other() {}